@@ -0,0 +1,12 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !nobounds
+// +build !nobounds
+
+package mat64
+
+// checkBounds specifies whether element accessors check their indices. Building
+// with the nobounds tag disables the checks.
+const checkBounds = true
@@ -0,0 +1,65 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !nobounds
+// +build !nobounds
+
+package mat64
+
+import (
+	"errors"
+
+	check "launchpad.net/gocheck"
+)
+
+func (s *S) TestBounds(c *check.C) {
+	m := NewDense(3, 4, []float64{
+		1, 2, 3, 4,
+		5, 6, 7, 8,
+		9, 10, 11, 12,
+	})
+	var v Dense
+	v.View(m, 1, 1, 2, 2)
+
+	for _, test := range []struct {
+		m    *Dense
+		r, c int
+	}{
+		{m, -1, 0},
+		{m, 0, -1},
+		{m, 3, 0},
+		{m, 0, 4},
+		// Reading past the last column of a view would otherwise
+		// reach the next row of the underlying matrix.
+		{&v, 0, 2},
+		{&v, 2, 0},
+	} {
+		err := Maybe(func() { test.m.At(test.r, test.c) })
+		c.Check(errors.Is(err, ErrIndexOutOfRange), check.Equals, true, check.Commentf("At(%d, %d)", test.r, test.c))
+		err = Maybe(func() { test.m.Set(test.r, test.c, 0) })
+		c.Check(errors.Is(err, ErrIndexOutOfRange), check.Equals, true, check.Commentf("Set(%d, %d)", test.r, test.c))
+	}
+	c.Check(m.At(1, 3), check.Equals, 8.)
+
+	// The vector accessors are checked in the same way.
+	for _, vec := range []Mutable{Vec{1, 2, 3}, m.ColView(1)} {
+		for _, idx := range [][2]int{{-1, 0}, {3, 0}, {0, 1}} {
+			err := Maybe(func() { vec.At(idx[0], idx[1]) })
+			c.Check(errors.Is(err, ErrIndexOutOfRange), check.Equals, true, check.Commentf("%T.At(%d, %d)", vec, idx[0], idx[1]))
+			err = Maybe(func() { vec.Set(idx[0], idx[1], 0) })
+			c.Check(errors.Is(err, ErrIndexOutOfRange), check.Equals, true, check.Commentf("%T.Set(%d, %d)", vec, idx[0], idx[1]))
+		}
+	}
+
+	// The unchecked accessors agree with the checked ones for valid
+	// indices.
+	for r := 0; r < 2; r++ {
+		for j := 0; j < 2; j++ {
+			c.Check(v.AtUnchecked(r, j), check.Equals, v.At(r, j))
+			v.SetUnchecked(r, j, -v.At(r, j))
+		}
+	}
+	c.Check(m.At(2, 2), check.Equals, -11.)
+	c.Check(v.AtUnchecked(0, 2), check.Equals, 8.)
+}
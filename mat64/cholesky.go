@@ -12,6 +12,10 @@ import (
 type CholeskyFactor struct {
 	L   *Dense
 	SPD bool
+
+	// norm is the 1-norm of the factorized matrix, recorded by Cholesky
+	// for RCond. It is zero for factors built as literals.
+	norm float64
 }
 
 // CholeskyL returns the left Cholesky decomposition of the matrix a and whether
@@ -45,7 +49,7 @@ func Cholesky(a *Dense) CholeskyFactor {
 		}
 	}
 
-	return CholeskyFactor{L: l, SPD: spd}
+	return CholeskyFactor{L: l, SPD: spd, norm: norm1(a)}
 }
 
 // CholeskyR returns the right Cholesky decomposition of the matrix a and whether
@@ -86,14 +90,14 @@ func CholeskyR(a *Dense) (r *Dense, spd bool) {
 // matrix b is overwritten by the operation.
 func (f CholeskyFactor) Solve(b *Dense) (x *Dense) {
 	if !f.SPD {
-		panic("mat64: matrix not symmetric positive definite")
+		panic(opError(ErrNotPosDef, "CholeskyFactor.Solve", f.L, b))
 	}
 	l := f.L
 
 	_, n := l.Dims()
-	_, bn := b.Dims()
-	if n != bn {
-		panic(ErrShape)
+	bm, bn := b.Dims()
+	if bm != n {
+		panic(opError(ErrShape, "CholeskyFactor.Solve", l, b))
 	}
 
 	nx := bn
@@ -121,3 +125,48 @@ func (f CholeskyFactor) Solve(b *Dense) (x *Dense) {
 
 	return x
 }
+
+// RCond returns an estimate of the reciprocal of the 1-norm condition number of
+// the symmetric positive definite matrix a = l.l', 1/(norm(a, 1)*norm(inv(a), 1)),
+// computed as for LUFactors.RCond. RCond returns zero if a is not symmetric
+// positive definite, and panics with ErrNoNorm if the factors were not computed
+// by Cholesky, which records the norm of a.
+func (f CholeskyFactor) RCond() float64 {
+	if !f.SPD {
+		return 0
+	}
+	if f.norm == 0 {
+		panic(opError(ErrNoNorm, "CholeskyFactor.RCond", f.L))
+	}
+	n, _ := f.L.Dims()
+	return 1 / (f.norm * normEst1(n, f.solveVec, f.solveTransVec))
+}
+
+// SolveRefine returns the solution x of a.x = b for the symmetric positive definite
+// matrix a = l.l', refined and reported on as for LUFactors.SolveRefine. The
+// matrices a and b are not altered.
+func (f CholeskyFactor) SolveRefine(a, b *Dense) (x *Dense, info SolveInfo, err error) {
+	return solveRefine(f, f.RCond(), a, b)
+}
+
+// solveTransVec overwrites x with the solution of a'*y = x, which for a
+// symmetric a is the solution of a*y = x.
+func (f CholeskyFactor) solveTransVec(x []float64) { f.solveVec(x) }
+
+// solveVec overwrites x with the solution of a*y = x.
+func (f CholeskyFactor) solveVec(x []float64) {
+	l := f.L
+	n := len(x)
+	for k := 0; k < n; k++ {
+		for i := 0; i < k; i++ {
+			x[k] -= x[i] * l.At(k, i)
+		}
+		x[k] /= l.At(k, k)
+	}
+	for k := n - 1; k >= 0; k-- {
+		for i := k + 1; i < n; i++ {
+			x[k] -= x[i] * l.At(i, k)
+		}
+		x[k] /= l.At(k, k)
+	}
+}
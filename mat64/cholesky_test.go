@@ -39,3 +39,26 @@ func (s *S) TestCholesky(c *check.C) {
 		c.Check(t.a.EqualsApprox(eye(), 1e-12), check.Equals, true)
 	}
 }
+
+func (s *S) TestCholeskyRCond(c *check.C) {
+	for i, a := range []*Dense{
+		NewDense(2, 2, []float64{4, 1, 1, 3}),
+		NewDense(3, 3, []float64{
+			4, -2, 1,
+			-2, 5, 0.5,
+			1, 0.5, 6,
+		}),
+		hilbert(5),
+		hilbert(9),
+	} {
+		want := rcond(a)
+		got := Cholesky(DenseCopyOf(a)).RCond()
+		c.Check(got >= want*(1-1e-6) && got <= 3*want, check.Equals, true,
+			check.Commentf("Test %d: got %v want %v", i, got, want))
+	}
+
+	c.Check(Cholesky(NewDense(2, 2, []float64{1, 2, 2, 1})).RCond(), check.Equals, 0.)
+
+	lit := CholeskyFactor{L: NewDense(1, 1, []float64{2}), SPD: true}
+	c.Check(func() { lit.RCond() }, check.PanicMatches, ".*do not record the norm.*")
+}
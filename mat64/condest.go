@@ -0,0 +1,108 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+)
+
+// maxNormEstIter is the maximum number of unit vectors tried by normEst1.
+const maxNormEstIter = 5
+
+// normEst1 returns an estimate of the 1-norm of an n-by-n matrix b that is
+// available only through the functions mul and mulTrans, which overwrite
+// their argument x with b*x and b'*x respectively. The estimate is a lower
+// bound on the norm, and is found by the method of Hager, "Condition
+// estimates", SIAM J. Sci. Stat. Comput. 5, 1984, as refined by Higham,
+// "FORTRAN codes for estimating the one-norm of a real or complex matrix",
+// ACM Trans. Math. Soft. 14(4), 1988.
+func normEst1(n int, mul, mulTrans func(x []float64)) float64 {
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = 1 / float64(n)
+	}
+	mul(x)
+	if n == 1 {
+		return math.Abs(x[0])
+	}
+	est := asum(x)
+
+	sgn := make([]float64, n)
+	for i, v := range x {
+		sgn[i] = sign(v)
+	}
+	copy(x, sgn)
+	mulTrans(x)
+	j := iamax(x)
+
+	for iter := 0; iter < maxNormEstIter; iter++ {
+		for i := range x {
+			x[i] = 0
+		}
+		x[j] = 1
+		mul(x)
+		old := est
+		est = asum(x)
+
+		same := true
+		for i, v := range x {
+			if sign(v) != sgn[i] {
+				same = false
+				break
+			}
+		}
+		if same || est <= old {
+			est = math.Max(est, old)
+			break
+		}
+
+		for i, v := range x {
+			sgn[i] = sign(v)
+		}
+		copy(x, sgn)
+		mulTrans(x)
+		last := j
+		j = iamax(x)
+		if math.Abs(x[last]) == math.Abs(x[j]) {
+			break
+		}
+	}
+
+	// Guard against a poor estimate with an alternating vector
+	// whose image is large for matrices where the search fails.
+	for i := range x {
+		x[i] = 1 + float64(i)/float64(n-1)
+		if i%2 == 1 {
+			x[i] = -x[i]
+		}
+	}
+	mul(x)
+	return math.Max(est, 2*asum(x)/float64(3*n))
+}
+
+func sign(v float64) float64 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
+
+func asum(x []float64) float64 {
+	var s float64
+	for _, v := range x {
+		s += math.Abs(v)
+	}
+	return s
+}
+
+func iamax(x []float64) int {
+	var j int
+	for i, v := range x {
+		if math.Abs(v) > math.Abs(x[j]) {
+			j = i
+		}
+	}
+	return j
+}
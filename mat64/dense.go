@@ -29,18 +29,21 @@ var (
 	_ Viewer      = matrix
 	_ Submatrixer = matrix
 	_ RowViewer   = matrix
+	_ ColViewer   = matrix
 
 	_ Adder     = matrix
 	_ Suber     = matrix
 	_ Muler     = matrix
 	_ Dotter    = matrix
 	_ ElemMuler = matrix
+	_ ElemDiver = matrix
 
-	_ Scaler  = matrix
-	_ Applyer = matrix
+	_ Scaler        = matrix
+	_ Applyer       = matrix
+	_ BinaryApplyer = matrix
 
 	_ TransposeCopier = matrix
-	// _ TransposeViewer = matrix
+	_ Transposer      = matrix
 
 	_ Tracer = matrix
 	_ Normer = matrix
@@ -65,7 +68,7 @@ type Dense struct {
 
 func NewDense(r, c int, mat []float64) *Dense {
 	if mat != nil && r*c != len(mat) {
-		panic(ErrShape)
+		panic(dimsError(ErrShape, "NewDense", [2]int{r, c}, [2]int{len(mat), 1}))
 	}
 	if mat == nil {
 		mat = make([]float64, r*c)
@@ -99,11 +102,36 @@ func (m *Dense) isZero() bool {
 	return m.mat.Cols == 0 || m.mat.Rows == 0
 }
 
+// At returns the element at row r and column c. At will panic with ErrIndexOutOfRange
+// if r or c is out of range, unless built with the nobounds tag.
 func (m *Dense) At(r, c int) float64 {
+	if checkBounds && (uint(r) >= uint(m.mat.Rows) || uint(c) >= uint(m.mat.Cols)) {
+		panic(indexError("Dense.At", m, r, c))
+	}
 	return m.mat.Data[r*m.mat.Stride+c]
 }
 
+// Set sets the element at row r and column c to v. Set will panic with
+// ErrIndexOutOfRange if r or c is out of range, unless built with the nobounds tag.
 func (m *Dense) Set(r, c int, v float64) {
+	if checkBounds && (uint(r) >= uint(m.mat.Rows) || uint(c) >= uint(m.mat.Cols)) {
+		panic(indexError("Dense.Set", m, r, c))
+	}
+	m.mat.Data[r*m.mat.Stride+c] = v
+}
+
+// AtUnchecked returns the element at row r and column c without checking that r
+// and c are in range. It is intended for hot loops whose indices are known to be
+// valid; an out of range column reads an element of another row or of the matrix
+// underlying a view.
+func (m *Dense) AtUnchecked(r, c int) float64 {
+	return m.mat.Data[r*m.mat.Stride+c]
+}
+
+// SetUnchecked sets the element at row r and column c to v without checking that
+// r and c are in range. As for AtUnchecked, the caller must ensure the indices are
+// valid.
+func (m *Dense) SetUnchecked(r, c int, v float64) {
 	m.mat.Data[r*m.mat.Stride+c] = v
 }
 
@@ -111,7 +139,7 @@ func (m *Dense) Dims() (r, c int) { return m.mat.Rows, m.mat.Cols }
 
 func (m *Dense) Col(col []float64, c int) []float64 {
 	if c >= m.mat.Cols || c < 0 {
-		panic(ErrIndexOutOfRange)
+		panic(indexError("Dense.Col", m, c))
 	}
 
 	if col == nil {
@@ -128,7 +156,7 @@ func (m *Dense) Col(col []float64, c int) []float64 {
 
 func (m *Dense) SetCol(c int, v []float64) int {
 	if c >= m.mat.Cols || c < 0 {
-		panic(ErrIndexOutOfRange)
+		panic(indexError("Dense.SetCol", m, c))
 	}
 
 	if blasEngine == nil {
@@ -141,7 +169,7 @@ func (m *Dense) SetCol(c int, v []float64) int {
 
 func (m *Dense) Row(row []float64, r int) []float64 {
 	if r >= m.mat.Rows || r < 0 {
-		panic(ErrIndexOutOfRange)
+		panic(indexError("Dense.Row", m, r))
 	}
 
 	if row == nil {
@@ -154,7 +182,7 @@ func (m *Dense) Row(row []float64, r int) []float64 {
 
 func (m *Dense) SetRow(r int, v []float64) int {
 	if r >= m.mat.Rows || r < 0 {
-		panic(ErrIndexOutOfRange)
+		panic(indexError("Dense.SetRow", m, r))
 	}
 
 	copy(m.rowView(r), v)
@@ -164,7 +192,7 @@ func (m *Dense) SetRow(r int, v []float64) int {
 
 func (m *Dense) RowView(r int) []float64 {
 	if r >= m.mat.Rows || r < 0 {
-		panic(ErrIndexOutOfRange)
+		panic(indexError("Dense.RowView", m, r))
 	}
 	return m.rowView(r)
 }
@@ -183,8 +211,9 @@ func (m *Dense) View(a Matrix, i, j, r, c int) {
 
 func (m *Dense) Submatrix(a Matrix, i, j, r, c int) {
 	// This is probably a bad idea, but for the moment, we do it.
-	m.View(a, i, j, r, c)
-	m.Clone(m)
+	var v Dense
+	v.View(a, i, j, r, c)
+	m.Clone(&v)
 }
 
 func (m *Dense) Clone(a Matrix) {
@@ -237,7 +266,7 @@ func (m *Dense) Copy(a Matrix) (r, c int) {
 	default:
 		for i := 0; i < r; i++ {
 			for j := 0; j < c; j++ {
-				m.Set(r, c, a.At(r, c))
+				m.Set(i, j, a.At(i, j))
 			}
 		}
 	}
@@ -267,7 +296,7 @@ func (m *Dense) Max() float64 {
 
 func (m *Dense) Trace() float64 {
 	if m.mat.Rows != m.mat.Cols {
-		panic(ErrSquare)
+		panic(opError(ErrSquare, "Dense.Trace", m))
 	}
 	var t float64
 	for i := 0; i < len(m.mat.Data); i += m.mat.Stride + 1 {
@@ -332,13 +361,13 @@ func (m *Dense) Norm(ord float64) float64 {
 		}
 		return math.Sqrt(n)
 	case ord == 2, ord == -2:
-		s := SVD(m, epsilon, small, false, false).Sigma
+		s := SVDJobs(m, epsilon, small, SVDNone, SVDNone, true).Sigma
 		if ord == 2 {
 			return s[0]
 		}
 		return s[len(s)-1]
 	default:
-		panic(ErrNormOrder)
+		panic(opError(ErrNormOrder, "Dense.Norm", m))
 	}
 
 	return n
@@ -349,7 +378,7 @@ func (m *Dense) Add(a, b Matrix) {
 	br, bc := b.Dims()
 
 	if ar != br || ac != bc {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.Add", m, a, b))
 	}
 
 	if m.isZero() {
@@ -361,7 +390,7 @@ func (m *Dense) Add(a, b Matrix) {
 			Data:   use(m.mat.Data, ar*ac),
 		}
 	} else if ar != m.mat.Rows || ac != m.mat.Cols {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.Add", m, a, b))
 	}
 
 	if a, ok := a.(RawMatrixer); ok {
@@ -403,7 +432,7 @@ func (m *Dense) Sub(a, b Matrix) {
 	br, bc := b.Dims()
 
 	if ar != br || ac != bc {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.Sub", m, a, b))
 	}
 
 	if m.isZero() {
@@ -415,7 +444,7 @@ func (m *Dense) Sub(a, b Matrix) {
 			Data:   use(m.mat.Data, ar*ac),
 		}
 	} else if ar != m.mat.Rows || ac != m.mat.Cols {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.Sub", m, a, b))
 	}
 
 	if a, ok := a.(RawMatrixer); ok {
@@ -457,7 +486,7 @@ func (m *Dense) MulElem(a, b Matrix) {
 	br, bc := b.Dims()
 
 	if ar != br || ac != bc {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.MulElem", m, a, b))
 	}
 
 	if m.isZero() {
@@ -469,7 +498,7 @@ func (m *Dense) MulElem(a, b Matrix) {
 			Data:   use(m.mat.Data, ar*ac),
 		}
 	} else if ar != m.mat.Rows || ac != m.mat.Cols {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.MulElem", m, a, b))
 	}
 
 	if a, ok := a.(RawMatrixer); ok {
@@ -511,7 +540,7 @@ func (m *Dense) Dot(b Matrix) float64 {
 	br, bc := b.Dims()
 
 	if mr != br || mc != bc {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.Dot", m, b))
 	}
 
 	var d float64
@@ -552,11 +581,14 @@ func (m *Dense) Mul(a, b Matrix) {
 	br, bc := b.Dims()
 
 	if ac != br {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.Mul", m, a, b))
 	}
 
+	au, aTrans := untranspose(a)
+	bu, bTrans := untranspose(b)
+
 	var w Dense
-	if m != a && m != b {
+	if m != au && m != bu {
 		w = *m
 	}
 	if w.isZero() {
@@ -568,18 +600,18 @@ func (m *Dense) Mul(a, b Matrix) {
 			Data:   use(w.mat.Data, ar*bc),
 		}
 	} else if ar != w.mat.Rows || bc != w.mat.Cols {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.Mul", m, a, b))
 	}
 
-	if a, ok := a.(RawMatrixer); ok {
-		if b, ok := b.(RawMatrixer); ok {
+	if a, ok := au.(RawMatrixer); ok {
+		if b, ok := bu.(RawMatrixer); ok {
 			amat, bmat := a.RawMatrix(), b.RawMatrix()
 			if blasEngine == nil {
 				panic(ErrNoEngine)
 			}
 			blasEngine.Dgemm(
 				BlasOrder,
-				blas.NoTrans, blas.NoTrans,
+				aTrans, bTrans,
 				ar, bc, ac,
 				1.,
 				amat.Data, amat.Stride,
@@ -600,7 +632,7 @@ func (m *Dense) Mul(a, b Matrix) {
 			}
 			for r := 0; r < ar; r++ {
 				for c := 0; c < bc; c++ {
-					w.mat.Data[r*w.mat.Stride+w.mat.Cols] = blasEngine.Ddot(ac, a.Row(row, r), 1, b.Col(col, c), 1)
+					w.mat.Data[r*w.mat.Stride+c] = blasEngine.Ddot(ac, a.Row(row, r), 1, b.Col(col, c), 1)
 				}
 			}
 			*m = w
@@ -618,7 +650,7 @@ func (m *Dense) Mul(a, b Matrix) {
 			for i, e := range row {
 				v += e * b.At(i, c)
 			}
-			w.mat.Data[r*w.mat.Stride+w.mat.Cols] = v
+			w.mat.Data[r*w.mat.Stride+c] = v
 		}
 	}
 	*m = w
@@ -636,7 +668,7 @@ func (m *Dense) Scale(f float64, a Matrix) {
 			Data:   use(m.mat.Data, ar*ac),
 		}
 	} else if ar != m.mat.Rows || ac != m.mat.Cols {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.Scale", m, a))
 	}
 
 	if a, ok := a.(RawMatrixer); ok {
@@ -679,7 +711,7 @@ func (m *Dense) Apply(f ApplyFunc, a Matrix) {
 			Data:   use(m.mat.Data, ar*ac),
 		}
 	} else if ar != m.mat.Rows || ac != m.mat.Cols {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.Apply", m, a))
 	}
 
 	if a, ok := a.(RawMatrixer); ok {
@@ -720,7 +752,7 @@ func zero(f []float64) {
 func (m *Dense) U(a Matrix) {
 	ar, ac := a.Dims()
 	if ar != ac {
-		panic(ErrSquare)
+		panic(opError(ErrSquare, "Dense.U", m, a))
 	}
 
 	switch {
@@ -736,7 +768,7 @@ func (m *Dense) U(a Matrix) {
 			Data:   use(m.mat.Data, ar*ac),
 		}
 	case ar != m.mat.Rows || ac != m.mat.Cols:
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.U", m, a))
 	}
 
 	if a, ok := a.(RawMatrixer); ok {
@@ -776,7 +808,7 @@ func (m *Dense) zeroLower() {
 func (m *Dense) L(a Matrix) {
 	ar, ac := a.Dims()
 	if ar != ac {
-		panic(ErrSquare)
+		panic(opError(ErrSquare, "Dense.L", m, a))
 	}
 
 	switch {
@@ -792,7 +824,7 @@ func (m *Dense) L(a Matrix) {
 			Data:   use(m.mat.Data, ar*ac),
 		}
 	case ar != m.mat.Rows || ac != m.mat.Cols:
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.L", m, a))
 	}
 
 	if a, ok := a.(RawMatrixer); ok {
@@ -844,7 +876,7 @@ func (m *Dense) TCopy(a Matrix) {
 		}
 		w.mat.Stride = ar
 	} else if ar != m.mat.Cols || ac != m.mat.Rows {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.TCopy", m, a))
 	}
 	switch a := a.(type) {
 	case *Dense:
@@ -867,7 +899,7 @@ func (m *Dense) Stack(a, b Matrix) {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
 	if ac != bc || m == a || m == b {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.Stack", m, a, b))
 	}
 
 	if m.isZero() {
@@ -879,7 +911,7 @@ func (m *Dense) Stack(a, b Matrix) {
 			Data:   use(m.mat.Data, (ar+br)*ac),
 		}
 	} else if ar+br != m.mat.Rows || ac != m.mat.Cols {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.Stack", m, a, b))
 	}
 
 	m.Copy(a)
@@ -892,7 +924,7 @@ func (m *Dense) Augment(a, b Matrix) {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
 	if ar != br || m == a || m == b {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.Augment", m, a, b))
 	}
 
 	if m.isZero() {
@@ -904,7 +936,7 @@ func (m *Dense) Augment(a, b Matrix) {
 			Data:   use(m.mat.Data, ar*(ac+bc)),
 		}
 	} else if ar != m.mat.Rows || ac+bc != m.mat.Cols {
-		panic(ErrShape)
+		panic(opError(ErrShape, "Dense.Augment", m, a, b))
 	}
 
 	m.Copy(a)
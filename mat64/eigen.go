@@ -41,7 +41,7 @@ type EigenFactors struct {
 func Eigen(a *Dense, epsilon float64) EigenFactors {
 	m, n := a.Dims()
 	if m != n {
-		panic(ErrSquare)
+		panic(opError(ErrSquare, "Eigen", a))
 	}
 
 	var v *Dense
@@ -404,6 +404,163 @@ func cdiv(xr, xi, yr, yi float64) (float64, float64) {
 // Vol.ii-Linear Algebra, and the corresponding
 // Fortran subroutine in EISPACK.
 func hqr2(d, e []float64, hess, v *Dense, epsilon float64) {
+	norm := hqr(d, e, hess, v, epsilon)
+
+	nn := len(d)
+	low := 0
+	high := nn - 1
+
+	var p, q, r, s, z, t, w, x, y float64
+
+	// Backsubstitute to find vectors of upper triangular form
+	if norm == 0 {
+		return
+	}
+
+	for n := nn - 1; n >= 0; n-- {
+		p = d[n]
+		q = e[n]
+
+		if q == 0 {
+			// Real vector
+			l := n
+			hess.Set(n, n, 1)
+			for i := n - 1; i >= 0; i-- {
+				w = hess.At(i, i) - p
+				r = 0
+				for j := l; j <= n; j++ {
+					r += hess.At(i, j) * hess.At(j, n)
+				}
+				if e[i] < 0 {
+					z = w
+					s = r
+				} else {
+					l = i
+					if e[i] == 0 {
+						if w != 0 {
+							hess.Set(i, n, -r/w)
+						} else {
+							hess.Set(i, n, -r/(epsilon*norm))
+						}
+					} else {
+						// Solve real equations
+						x = hess.At(i, i+1)
+						y = hess.At(i+1, i)
+						q = (d[i]-p)*(d[i]-p) + e[i]*e[i]
+						t = (x*s - z*r) / q
+						hess.Set(i, n, t)
+						if math.Abs(x) > math.Abs(z) {
+							hess.Set(i+1, n, (-r-w*t)/x)
+						} else {
+							hess.Set(i+1, n, (-s-y*t)/z)
+						}
+					}
+
+					// Overflow control
+					t = math.Abs(hess.At(i, n))
+					if epsilon*t*t > 1 {
+						for j := i; j <= n; j++ {
+							hess.Set(j, n, hess.At(j, n)/t)
+						}
+					}
+				}
+			}
+		} else if q < 0 {
+			// Complex vector
+
+			l := n - 1
+
+			// Last vector component imaginary so matrix is triangular
+			if math.Abs(hess.At(n, n-1)) > math.Abs(hess.At(n-1, n)) {
+				hess.Set(n-1, n-1, q/hess.At(n, n-1))
+				hess.Set(n-1, n, -(hess.At(n, n)-p)/hess.At(n, n-1))
+			} else {
+				re, im := cdiv(0, -hess.At(n-1, n), hess.At(n-1, n-1)-p, q)
+				hess.Set(n-1, n-1, re)
+				hess.Set(n-1, n, im)
+			}
+			hess.Set(n, n-1, 0)
+			hess.Set(n, n, 1)
+
+			for i := n - 2; i >= 0; i-- {
+				var ra, sa, vr, vi float64
+				for j := l; j <= n; j++ {
+					ra += hess.At(i, j) * hess.At(j, n-1)
+					sa += hess.At(i, j) * hess.At(j, n)
+				}
+				w = hess.At(i, i) - p
+
+				if e[i] < 0 {
+					z = w
+					r = ra
+					s = sa
+				} else {
+					l = i
+					if e[i] == 0 {
+						re, im := cdiv(-ra, -sa, w, q)
+						hess.Set(i, n-1, re)
+						hess.Set(i, n, im)
+					} else {
+						// Solve complex equations
+						x = hess.At(i, i+1)
+						y = hess.At(i+1, i)
+						vr = (d[i]-p)*(d[i]-p) + e[i]*e[i] - q*q
+						vi = (d[i] - p) * 2 * q
+						if vr == 0 && vi == 0 {
+							vr = epsilon * norm * (math.Abs(w) + math.Abs(q) + math.Abs(x) + math.Abs(y) + math.Abs(z))
+						}
+						re, im := cdiv(x*r-z*ra+q*sa, x*s-z*sa-q*ra, vr, vi)
+						hess.Set(i, n-1, re)
+						hess.Set(i, n, im)
+						if math.Abs(x) > (math.Abs(z) + math.Abs(q)) {
+							hess.Set(i+1, n-1, (-ra-w*hess.At(i, n-1)+q*hess.At(i, n))/x)
+							hess.Set(i+1, n, (-sa-w*hess.At(i, n)-q*hess.At(i, n-1))/x)
+						} else {
+							re, im := cdiv(-r-y*hess.At(i, n-1), -s-y*hess.At(i, n), z, q)
+							hess.Set(i+1, n-1, re)
+							hess.Set(i+1, n, im)
+						}
+					}
+
+					// Overflow control
+					t = math.Max(math.Abs(hess.At(i, n-1)), math.Abs(hess.At(i, n)))
+					if (epsilon*t)*t > 1 {
+						for j := i; j <= n; j++ {
+							hess.Set(j, n-1, hess.At(j, n-1)/t)
+							hess.Set(j, n, hess.At(j, n)/t)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// Vectors of isolated roots
+	for i := 0; i < nn; i++ {
+		if i < low || i > high {
+			for j := i; j < nn; j++ {
+				v.Set(i, j, hess.At(i, j))
+			}
+		}
+	}
+
+	// Back transformation to get eigenvectors of original matrix
+	for j := nn - 1; j >= low; j-- {
+		for i := low; i <= high; i++ {
+			z = 0
+			for k := low; k <= min(j, high); k++ {
+				z += v.At(i, k) * hess.At(k, j)
+			}
+			v.Set(i, j, z)
+		}
+	}
+}
+
+// hqr reduces the upper Hessenberg matrix hess to real Schur form by the
+// iteration of hqr2, accumulating the transformations in v and storing the
+// real and imaginary parts of the eigenvalues in d and e. It returns the norm
+// of the original hess used for the convergence tests.
+func hqr(d, e []float64, hess, v *Dense, epsilon float64) (norm float64) {
 	// Initialize
 	nn := len(d)
 	n := nn - 1
@@ -411,10 +568,9 @@ func hqr2(d, e []float64, hess, v *Dense, epsilon float64) {
 	low := 0
 	high := n
 
-	var exshift, p, q, r, s, z, t, w, x, y float64
+	var exshift, p, q, r, s, z, w, x, y float64
 
 	// Store roots isolated by balanc and compute matrix norm
-	var norm float64
 	for i := 0; i < nn; i++ {
 		if i < low || i > high {
 			d[i] = hess.At(i, i)
@@ -474,11 +630,17 @@ func hqr2(d, e []float64, hess, v *Dense, epsilon float64) {
 				e[n] = 0
 				x = hess.At(n, n-1)
 				s = math.Abs(x) + math.Abs(z)
-				p = x / s
-				q = z / s
-				r = math.Hypot(p, q)
-				p /= r
-				q /= r
+				if s == 0 {
+					// The block is zero and already
+					// triangular.
+					p, q = 0, 1
+				} else {
+					p = x / s
+					q = z / s
+					r = math.Hypot(p, q)
+					p /= r
+					q /= r
+				}
 
 				// Row modification
 				for j := n - 1; j < nn; j++ {
@@ -659,148 +821,7 @@ func hqr2(d, e []float64, hess, v *Dense, epsilon float64) {
 		}
 	}
 
-	// Backsubstitute to find vectors of upper triangular form
-	if norm == 0 {
-		return
-	}
-
-	for n = nn - 1; n >= 0; n-- {
-		p = d[n]
-		q = e[n]
-
-		if q == 0 {
-			// Real vector
-			l := n
-			hess.Set(n, n, 1)
-			for i := n - 1; i >= 0; i-- {
-				w = hess.At(i, i) - p
-				r = 0
-				for j := l; j <= n; j++ {
-					r += hess.At(i, j) * hess.At(j, n)
-				}
-				if e[i] < 0 {
-					z = w
-					s = r
-				} else {
-					l = i
-					if e[i] == 0 {
-						if w != 0 {
-							hess.Set(i, n, -r/w)
-						} else {
-							hess.Set(i, n, -r/(epsilon*norm))
-						}
-					} else {
-						// Solve real equations
-						x = hess.At(i, i+1)
-						y = hess.At(i+1, i)
-						q = (d[i]-p)*(d[i]-p) + e[i]*e[i]
-						t = (x*s - z*r) / q
-						hess.Set(i, n, t)
-						if math.Abs(x) > math.Abs(z) {
-							hess.Set(i+1, n, (-r-w*t)/x)
-						} else {
-							hess.Set(i+1, n, (-s-y*t)/z)
-						}
-					}
-
-					// Overflow control
-					t = math.Abs(hess.At(i, n))
-					if epsilon*t*t > 1 {
-						for j := i; j <= n; j++ {
-							hess.Set(j, n, hess.At(j, n)/t)
-						}
-					}
-				}
-			}
-		} else if q < 0 {
-			// Complex vector
-
-			l := n - 1
-
-			// Last vector component imaginary so matrix is triangular
-			if math.Abs(hess.At(n, n-1)) > math.Abs(hess.At(n-1, n)) {
-				hess.Set(n-1, n-1, q/hess.At(n, n-1))
-				hess.Set(n-1, n, -(hess.At(n, n)-p)/hess.At(n, n-1))
-			} else {
-				re, im := cdiv(0, -hess.At(n-1, n), hess.At(n-1, n-1)-p, q)
-				hess.Set(n-1, n-1, re)
-				hess.Set(n-1, n, im)
-			}
-			hess.Set(n, n-1, 0)
-			hess.Set(n, n, 1)
-
-			for i := n - 2; i >= 0; i-- {
-				var ra, sa, vr, vi float64
-				for j := l; j <= n; j++ {
-					ra += hess.At(i, j) * hess.At(j, n-1)
-					sa += hess.At(i, j) * hess.At(j, n)
-				}
-				w = hess.At(i, i) - p
-
-				if e[i] < 0 {
-					z = w
-					r = ra
-					s = sa
-				} else {
-					l = i
-					if e[i] == 0 {
-						re, im := cdiv(-ra, -sa, w, q)
-						hess.Set(i, n-1, re)
-						hess.Set(i, n, im)
-					} else {
-						// Solve complex equations
-						x = hess.At(i, i+1)
-						y = hess.At(i+1, i)
-						vr = (d[i]-p)*(d[i]-p) + e[i]*e[i] - q*q
-						vi = (d[i] - p) * 2 * q
-						if vr == 0 && vi == 0 {
-							vr = epsilon * norm * (math.Abs(w) + math.Abs(q) + math.Abs(x) + math.Abs(y) + math.Abs(z))
-						}
-						re, im := cdiv(x*r-z*ra+q*sa, x*s-z*sa-q*ra, vr, vi)
-						hess.Set(i, n-1, re)
-						hess.Set(i, n, im)
-						if math.Abs(x) > (math.Abs(z) + math.Abs(q)) {
-							hess.Set(i+1, n-1, (-ra-w*hess.At(i, n-1)+q*hess.At(i, n))/x)
-							hess.Set(i+1, n, (-sa-w*hess.At(i, n)-q*hess.At(i, n-1))/x)
-						} else {
-							re, im := cdiv(-r-y*hess.At(i, n-1), -s-y*hess.At(i, n), z, q)
-							hess.Set(i+1, n-1, re)
-							hess.Set(i+1, n, im)
-						}
-					}
-
-					// Overflow control
-					t = math.Max(math.Abs(hess.At(i, n-1)), math.Abs(hess.At(i, n)))
-					if (epsilon*t)*t > 1 {
-						for j := i; j <= n; j++ {
-							hess.Set(j, n-1, hess.At(j, n-1)/t)
-							hess.Set(j, n, hess.At(j, n)/t)
-						}
-					}
-				}
-			}
-		}
-	}
-
-	// Vectors of isolated roots
-	for i := 0; i < nn; i++ {
-		if i < low || i > high {
-			for j := i; j < nn; j++ {
-				v.Set(i, j, hess.At(i, j))
-			}
-		}
-	}
-
-	// Back transformation to get eigenvectors of original matrix
-	for j := nn - 1; j >= low; j-- {
-		for i := low; i <= high; i++ {
-			z = 0
-			for k := low; k <= min(j, high); k++ {
-				z += v.At(i, k) * hess.At(k, j)
-			}
-			v.Set(i, j, z)
-		}
-	}
+	return norm
 }
 
 // D returns the block diagonal eigenvalue matrix from the real and imaginary
@@ -809,7 +830,7 @@ func (f EigenFactors) D() *Dense {
 	d, e := f.d, f.e
 	var n int
 	if n = len(d); n != len(e) {
-		panic(ErrSquare)
+		panic(opError(ErrSquare, "EigenFactors.D"))
 	}
 	dm := NewDense(n, n, nil)
 	for i := 0; i < n; i++ {
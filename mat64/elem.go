@@ -0,0 +1,201 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+// DivElem performs element-wise division of a by b, placing the result in the
+// receiver.
+func (m *Dense) DivElem(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+
+	if ar != br || ac != bc {
+		panic(opError(ErrShape, "Dense.DivElem", m, a, b))
+	}
+
+	if m.isZero() {
+		m.mat = RawMatrix{
+			Order:  BlasOrder,
+			Rows:   ar,
+			Cols:   ac,
+			Stride: ac,
+			Data:   use(m.mat.Data, ar*ac),
+		}
+	} else if ar != m.mat.Rows || ac != m.mat.Cols {
+		panic(opError(ErrShape, "Dense.DivElem", m, a, b))
+	}
+
+	if a, ok := a.(RawMatrixer); ok {
+		if b, ok := b.(RawMatrixer); ok {
+			amat, bmat := a.RawMatrix(), b.RawMatrix()
+			for ja, jb, jm := 0, 0, 0; ja < ar*amat.Stride; ja, jb, jm = ja+amat.Stride, jb+bmat.Stride, jm+m.mat.Stride {
+				for i, v := range amat.Data[ja : ja+ac] {
+					m.mat.Data[i+jm] = v / bmat.Data[i+jb]
+				}
+			}
+			return
+		}
+	}
+
+	if a, ok := a.(Vectorer); ok {
+		if b, ok := b.(Vectorer); ok {
+			rowa := make([]float64, ac)
+			rowb := make([]float64, bc)
+			for r := 0; r < ar; r++ {
+				a.Row(rowa, r)
+				for i, v := range b.Row(rowb, r) {
+					rowa[i] /= v
+				}
+				copy(m.rowView(r), rowa)
+			}
+			return
+		}
+	}
+
+	for r := 0; r < ar; r++ {
+		for c := 0; c < ac; c++ {
+			m.Set(r, c, a.At(r, c)/b.At(r, c))
+		}
+	}
+}
+
+// ApplyBinary applies the function f to each pair of corresponding elements of
+// a and b, placing the resulting matrix in the receiver. Element-wise powers,
+// maxima, minima and comparisons may be formed this way, for example
+//  m.ApplyBinary(func(_, _ int, x, y float64) float64 { return math.Max(x, y) }, a, b)
+func (m *Dense) ApplyBinary(f ApplyBinaryFunc, a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+
+	if ar != br || ac != bc {
+		panic(opError(ErrShape, "Dense.ApplyBinary", m, a, b))
+	}
+
+	if m.isZero() {
+		m.mat = RawMatrix{
+			Order:  BlasOrder,
+			Rows:   ar,
+			Cols:   ac,
+			Stride: ac,
+			Data:   use(m.mat.Data, ar*ac),
+		}
+	} else if ar != m.mat.Rows || ac != m.mat.Cols {
+		panic(opError(ErrShape, "Dense.ApplyBinary", m, a, b))
+	}
+
+	if a, ok := a.(RawMatrixer); ok {
+		if b, ok := b.(RawMatrixer); ok {
+			amat, bmat := a.RawMatrix(), b.RawMatrix()
+			for j, ja, jb, jm := 0, 0, 0, 0; ja < ar*amat.Stride; j, ja, jb, jm = j+1, ja+amat.Stride, jb+bmat.Stride, jm+m.mat.Stride {
+				for i, v := range amat.Data[ja : ja+ac] {
+					m.mat.Data[i+jm] = f(j, i, v, bmat.Data[i+jb])
+				}
+			}
+			return
+		}
+	}
+
+	for r := 0; r < ar; r++ {
+		for c := 0; c < ac; c++ {
+			m.Set(r, c, f(r, c, a.At(r, c), b.At(r, c)))
+		}
+	}
+}
+
+// broadcast copies a into the receiver, allocating it if it is zero, and
+// returns the elements of the vector v as BLAS vector data. v must have n
+// elements held in a single row or column. If v shares storage with the
+// receiver its elements are copied first, so that neither the copy of a nor
+// the updates that follow change them.
+func (m *Dense) broadcast(op string, a, v Matrix, n int) (data []float64, inc int) {
+	ar, ac := a.Dims()
+	vr, vc := v.Dims()
+
+	if vr*vc != n || (vr != 1 && vc != 1) {
+		panic(opError(ErrShape, op, m, a, v))
+	}
+
+	if m.isZero() {
+		m.mat = RawMatrix{
+			Order:  BlasOrder,
+			Rows:   ar,
+			Cols:   ac,
+			Stride: ac,
+			Data:   use(m.mat.Data, ar*ac),
+		}
+	} else if ar != m.mat.Rows || ac != m.mat.Cols {
+		panic(opError(ErrShape, op, m, a, v))
+	}
+
+	if vc != 1 {
+		v = transposeIf(v, true)
+	}
+	data, inc = vectorOf(v)
+	if overlaps(m.mat.Data, 1, (m.mat.Rows-1)*m.mat.Stride+m.mat.Cols, data, inc, n) {
+		t := make([]float64, n)
+		blasEngine.Dcopy(n, data, inc, t, 1)
+		data, inc = t, 1
+	}
+	if m != a {
+		m.Copy(a)
+	}
+	return data, inc
+}
+
+// AddRowVec adds the row vector v to each row of a, placing the result in the
+// receiver. v must have as many elements as a has columns, held in a single
+// row or column.
+func (m *Dense) AddRowVec(a, v Matrix) {
+	_, ac := a.Dims()
+	vd, vi := m.broadcast("Dense.AddRowVec", a, v, ac)
+	if ac == 0 {
+		return
+	}
+	for r := 0; r < m.mat.Rows; r++ {
+		blasEngine.Daxpy(ac, 1, vd, vi, m.rowView(r), 1)
+	}
+}
+
+// AddColVec adds the column vector v to each column of a, placing the result in
+// the receiver. v must have as many elements as a has rows, held in a single
+// row or column.
+func (m *Dense) AddColVec(a, v Matrix) {
+	ar, _ := a.Dims()
+	vd, vi := m.broadcast("Dense.AddColVec", a, v, ar)
+	for r := 0; r < ar; r++ {
+		f := vd[r*vi]
+		row := m.rowView(r)
+		for i := range row {
+			row[i] += f
+		}
+	}
+}
+
+// ScaleRows multiplies each row of a by the corresponding element of v, placing
+// the result in the receiver, so that m = diag(v)*a. v must have as many
+// elements as a has rows, held in a single row or column.
+func (m *Dense) ScaleRows(a, v Matrix) {
+	ar, ac := a.Dims()
+	vd, vi := m.broadcast("Dense.ScaleRows", a, v, ar)
+	if ac == 0 {
+		return
+	}
+	for r := 0; r < ar; r++ {
+		blasEngine.Dscal(ac, vd[r*vi], m.rowView(r), 1)
+	}
+}
+
+// ScaleCols multiplies each column of a by the corresponding element of v,
+// placing the result in the receiver, so that m = a*diag(v). v must have as
+// many elements as a has columns, held in a single row or column.
+func (m *Dense) ScaleCols(a, v Matrix) {
+	_, ac := a.Dims()
+	vd, vi := m.broadcast("Dense.ScaleCols", a, v, ac)
+	for r := 0; r < m.mat.Rows; r++ {
+		row := m.rowView(r)
+		for i := range row {
+			row[i] *= vd[i*vi]
+		}
+	}
+}
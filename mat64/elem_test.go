@@ -0,0 +1,151 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+
+	check "launchpad.net/gocheck"
+)
+
+func (s *S) TestDivElem(c *check.C) {
+	a := NewDense(flatten([][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+	}))
+	b := NewDense(flatten([][]float64{
+		{2, -4, 0.5},
+		{8, 1, 3},
+	}))
+	want := NewDense(flatten([][]float64{
+		{0.5, -0.5, 6},
+		{0.5, 5, 2},
+	}))
+
+	for i, test := range []struct {
+		a, b Matrix
+	}{
+		{a: a, b: b},
+		{a: Transpose{a.T()}, b: Transpose{b.T()}},
+	} {
+		var m Dense
+		m.DivElem(test.a, test.b)
+		c.Check(m.Equals(want), check.Equals, true, check.Commentf("Test %d", i))
+	}
+
+	m := DenseCopyOf(a)
+	m.DivElem(m, b)
+	c.Check(m.Equals(want), check.Equals, true)
+
+	c.Check(func() { m.DivElem(a, NewDense(3, 2, nil)) }, check.PanicMatches, ".*dimension mismatch.*")
+}
+
+func (s *S) TestApplyBinary(c *check.C) {
+	a := NewDense(flatten([][]float64{
+		{1, -2, 3},
+		{4, 5, -6},
+	}))
+	b := NewDense(flatten([][]float64{
+		{2, 2, -1},
+		{0, 6, 1},
+	}))
+
+	for i, test := range []struct {
+		f    ApplyBinaryFunc
+		a, b Matrix
+		want [][]float64
+	}{
+		{
+			f:    func(_, _ int, x, y float64) float64 { return math.Max(x, y) },
+			a:    a,
+			b:    b,
+			want: [][]float64{{2, 2, 3}, {4, 6, 1}},
+		},
+		{
+			f:    func(_, _ int, x, y float64) float64 { return math.Pow(x, y) },
+			a:    a,
+			b:    Transpose{b.T()},
+			want: [][]float64{{1, 4, 1.0 / 3}, {1, 15625, -6}},
+		},
+		{
+			f: func(_, _ int, x, y float64) float64 {
+				if x < y {
+					return 1
+				}
+				return 0
+			},
+			a:    a,
+			b:    b,
+			want: [][]float64{{1, 1, 0}, {0, 1, 1}},
+		},
+		{
+			f:    func(r, c int, x, y float64) float64 { return float64(10*r+c) + x - y },
+			a:    a,
+			b:    b,
+			want: [][]float64{{-1, -3, 6}, {14, 10, 5}},
+		},
+	} {
+		var m Dense
+		m.ApplyBinary(test.f, test.a, test.b)
+		c.Check(m.EqualsApprox(NewDense(flatten(test.want)), 1e-14), check.Equals, true, check.Commentf("Test %d", i))
+	}
+}
+
+func (s *S) TestBroadcast(c *check.C) {
+	a := NewDense(flatten([][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+	}))
+	row := NewDense(1, 3, []float64{10, 20, 30})
+	col := Vec{-1, 2}
+
+	var m Dense
+	m.AddRowVec(a, row)
+	c.Check(m.Equals(NewDense(flatten([][]float64{{11, 22, 33}, {14, 25, 36}}))), check.Equals, true)
+	m.AddRowVec(a, Vec{10, 20, 30})
+	c.Check(m.Equals(NewDense(flatten([][]float64{{11, 22, 33}, {14, 25, 36}}))), check.Equals, true)
+
+	m.AddColVec(a, col)
+	c.Check(m.Equals(NewDense(flatten([][]float64{{0, 1, 2}, {6, 7, 8}}))), check.Equals, true)
+
+	m.ScaleRows(a, col)
+	c.Check(m.Equals(NewDense(flatten([][]float64{{-1, -2, -3}, {8, 10, 12}}))), check.Equals, true)
+
+	var sc Dense
+	sc.ScaleCols(a, row)
+	c.Check(sc.Equals(NewDense(flatten([][]float64{{10, 40, 90}, {40, 100, 180}}))), check.Equals, true)
+
+	// The receiver may be the matrix operand, and a view may be the vector.
+	b := DenseCopyOf(a)
+	b.AddColVec(b, a.ColView(0))
+	c.Check(b.Equals(NewDense(flatten([][]float64{{2, 3, 4}, {8, 9, 10}}))), check.Equals, true)
+
+	// The vector may be a view of the receiver, whether or not the
+	// receiver is also the matrix operand.
+	b = DenseCopyOf(a)
+	var r0 Vec
+	r0.View(b.T(), 0, 0, 3, 1)
+	b.AddRowVec(b, r0)
+	c.Check(b.Equals(NewDense(flatten([][]float64{{2, 4, 6}, {5, 7, 9}}))), check.Equals, true)
+
+	b = NewDense(2, 3, nil)
+	var r1 Vec
+	r1.View(b.T(), 0, 1, 3, 1)
+	b.SetRow(1, []float64{1, 2, 3})
+	b.AddRowVec(a, r1)
+	c.Check(b.Equals(NewDense(flatten([][]float64{{2, 4, 6}, {5, 7, 9}}))), check.Equals, true)
+
+	b = DenseCopyOf(a)
+	b.ScaleRows(b, b.ColView(0))
+	c.Check(b.Equals(NewDense(flatten([][]float64{{1, 2, 3}, {16, 20, 24}}))), check.Equals, true)
+
+	b = DenseCopyOf(a)
+	r0.View(b.T(), 0, 0, 3, 1)
+	b.ScaleCols(b, r0)
+	c.Check(b.Equals(NewDense(flatten([][]float64{{1, 4, 9}, {4, 10, 18}}))), check.Equals, true)
+
+	c.Check(func() { m.AddRowVec(a, col) }, check.PanicMatches, ".*dimension mismatch.*")
+	c.Check(func() { m.ScaleRows(a, NewDense(2, 2, nil)) }, check.PanicMatches, ".*dimension mismatch.*")
+}
@@ -0,0 +1,259 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+)
+
+// Thresholds below which row and column scaling are considered worthwhile,
+// as used by LAPACK's dlaqge.
+const (
+	equilibrateThresh = 0.1
+	safeMin           = 0x1p-1022
+)
+
+// Equilibration holds row and column scale factors for an m-by-n matrix a
+// such that the largest element in magnitude of each row and column of
+// diag(R)*a*diag(C) is close to one.
+type Equilibration struct {
+	// R and C hold the row and column scale factors.
+	R, C []float64
+
+	// RowCond is the ratio of the smallest to the largest R[i] and ColCond
+	// that of the smallest to the largest C[j]. A ratio of at least 0.1
+	// indicates that scaling is not worth doing.
+	RowCond, ColCond float64
+
+	// AMax is the largest element of a in magnitude.
+	AMax float64
+}
+
+// Equilibrate returns row and column scale factors for the matrix a, computed as
+// by LAPACK's dgeequ: each row is scaled by the reciprocal of its largest element
+// in magnitude and each column of the row scaled matrix then by the reciprocal
+// of its largest element. If pow2 is true the factors are rounded to powers of
+// two so that scaling introduces no rounding error, as by dgeequb; the largest
+// element of each row and column then lies in [0.5, 1).
+//
+// Equilibrate returns ErrSingular if a has a row or column of zeros.
+func Equilibrate(a Matrix, pow2 bool) (Equilibration, error) {
+	m, n := a.Dims()
+	e := Equilibration{
+		R: make([]float64, m),
+		C: make([]float64, n),
+	}
+
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			e.R[i] = math.Max(e.R[i], math.Abs(a.At(i, j)))
+		}
+		e.AMax = math.Max(e.AMax, e.R[i])
+	}
+	cond, err := invertScales(e.R, pow2)
+	if err != nil {
+		return Equilibration{}, err
+	}
+	e.RowCond = cond
+
+	e.ColCond, err = colScales(e.C, a, e.R, pow2)
+	if err != nil {
+		return Equilibration{}, err
+	}
+
+	return e, nil
+}
+
+// colScales places in c the column scale factors of diag(r)*a, or of a if r is
+// nil, and returns their condition ratio.
+func colScales(c []float64, a Matrix, r []float64, pow2 bool) (float64, error) {
+	m, n := a.Dims()
+	for j := 0; j < n; j++ {
+		c[j] = 0
+		for i := 0; i < m; i++ {
+			v := math.Abs(a.At(i, j))
+			if r != nil {
+				v *= r[i]
+			}
+			c[j] = math.Max(c[j], v)
+		}
+	}
+	return invertScales(c, pow2)
+}
+
+// invertScales replaces the row or column maxima in s with their reciprocals,
+// rounded to powers of two if pow2 is true, and returns the ratio of the
+// smallest to the largest maximum.
+func invertScales(s []float64, pow2 bool) (float64, error) {
+	if len(s) == 0 {
+		return 1, nil
+	}
+	lo, hi := math.Inf(1), 0.
+	for i, v := range s {
+		if v == 0 {
+			return 0, ErrSingular
+		}
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+		v = math.Min(math.Max(v, safeMin), 1/safeMin)
+		if pow2 {
+			_, exp := math.Frexp(v)
+			s[i] = math.Ldexp(1, -exp)
+		} else {
+			s[i] = 1 / v
+		}
+	}
+	return math.Max(lo, safeMin) / math.Min(hi, 1/safeMin), nil
+}
+
+// Needed returns whether row and column scaling are worthwhile, following
+// LAPACK's dlaqge: rows are scaled if RowCond is less than 0.1 or AMax is close
+// to underflow or overflow, and columns if ColCond is less than 0.1.
+func (e Equilibration) Needed() (rows, cols bool) {
+	smlnum := safeMin / epsilon
+	rows = e.RowCond < equilibrateThresh || e.AMax < smlnum || e.AMax > 1/smlnum
+	cols = e.ColCond < equilibrateThresh
+	return rows, cols
+}
+
+// Scale replaces a with diag(R)*a if rows is true and with a*diag(C) if cols
+// is true.
+func (e Equilibration) Scale(a *Dense, rows, cols bool) {
+	m, n := a.Dims()
+	if m != len(e.R) || n != len(e.C) {
+		panic(opError(ErrShape, "Equilibration.Scale", a))
+	}
+	for i := 0; i < m; i++ {
+		row := a.rowView(i)
+		for j := range row {
+			if rows {
+				row[j] *= e.R[i]
+			}
+			if cols {
+				row[j] *= e.C[j]
+			}
+		}
+	}
+}
+
+// ScaleRHS replaces the right hand side b of a.x = b with diag(R)*b, as needed
+// when the rows of a have been scaled.
+func (e Equilibration) ScaleRHS(b *Dense) {
+	m, _ := b.Dims()
+	if m != len(e.R) {
+		panic(opError(ErrShape, "Equilibration.ScaleRHS", b))
+	}
+	scaleRows(b, e.R)
+}
+
+// UnscaleSolution replaces the solution y of a system whose columns have been
+// scaled with x = diag(C)*y, the solution of the original system.
+func (e Equilibration) UnscaleSolution(y *Dense) {
+	n, _ := y.Dims()
+	if n != len(e.C) {
+		panic(opError(ErrShape, "Equilibration.UnscaleSolution", y))
+	}
+	scaleRows(y, e.C)
+}
+
+// scaleRows replaces b with diag(s)*b.
+func scaleRows(b *Dense, s []float64) {
+	for i, v := range s {
+		row := b.rowView(i)
+		for j := range row {
+			row[j] *= v
+		}
+	}
+}
+
+// EquilibratedLUFactors holds the LU factors of a square matrix a after row and
+// column scaling, diag(R)*a*diag(C), together with the scale factors.
+type EquilibratedLUFactors struct {
+	// Factors holds the LU factors of the scaled matrix.
+	Factors LUFactors
+
+	Equilibration
+
+	// Rows and Cols record whether the rows and the columns of a were scaled.
+	Rows, Cols bool
+}
+
+// LUEquilibrated performs an LU decomposition of the square matrix a after
+// scaling it by power of two row and column factors when Equilibrate and Needed
+// indicate it is worthwhile, as LAPACK's dgesvx does. Scaling improves the choice
+// of pivots and the accuracy of the singularity test for badly scaled matrices,
+// and since the factors are powers of two it introduces no rounding error. A
+// matrix with a row or column of zeros is factorized unscaled. The matrix a is
+// overwritten during the decomposition.
+func LUEquilibrated(a *Dense) EquilibratedLUFactors {
+	m, n := a.Dims()
+	if m != n {
+		panic(opError(ErrSquare, "LUEquilibrated", a))
+	}
+
+	var f EquilibratedLUFactors
+	e, err := Equilibrate(a, true)
+	if err == nil {
+		rows, cols := e.Needed()
+		if !rows && cols {
+			// The column factors were found for the row scaled matrix.
+			e.ColCond, err = colScales(e.C, a, nil, true)
+			_, cols = e.Needed()
+		}
+		if err == nil && (rows || cols) {
+			e.Scale(a, rows, cols)
+			f.Equilibration, f.Rows, f.Cols = e, rows, cols
+		}
+	}
+	f.Factors = LU(a)
+	return f
+}
+
+// IsSingular returns whether the scaled matrix, and so a, is singular.
+func (f EquilibratedLUFactors) IsSingular() bool {
+	return f.Factors.IsSingular()
+}
+
+// RCond returns an estimate of the reciprocal of the 1-norm condition number of
+// the scaled matrix diag(R)*a*diag(C), as for LUFactors.RCond. As for dgesvx,
+// this is the condition number that bounds the error of Solve.
+func (f EquilibratedLUFactors) RCond() float64 {
+	return f.Factors.RCond()
+}
+
+// Solve computes the solution of a.x = b where b has as many rows as a by
+// solving with the factors of the scaled matrix and undoing the column
+// scaling. Solve panics if a is singular. The matrix b is overwritten
+// during the call.
+func (f EquilibratedLUFactors) Solve(b *Dense) (x *Dense) {
+	if bm, _ := b.Dims(); f.Rows && bm != len(f.R) {
+		panic(opError(ErrShape, "EquilibratedLUFactors.Solve", f.Factors.LU, b))
+	}
+	if f.Rows {
+		scaleRows(b, f.R)
+	}
+	x = f.Factors.Solve(b)
+	if f.Cols {
+		scaleRows(x, f.C)
+	}
+	return x
+}
+
+// SolveTrans computes the solution of a'.x = b where b has as many rows as a,
+// as for Solve. The roles of the row and column factors are exchanged, since
+// the transpose of the scaled matrix is diag(C)*a'*diag(R).
+func (f EquilibratedLUFactors) SolveTrans(b *Dense) (x *Dense) {
+	if bm, _ := b.Dims(); f.Cols && bm != len(f.C) {
+		panic(opError(ErrShape, "EquilibratedLUFactors.SolveTrans", f.Factors.LU, b))
+	}
+	if f.Cols {
+		scaleRows(b, f.C)
+	}
+	x = f.Factors.SolveTrans(b)
+	if f.Rows {
+		scaleRows(x, f.R)
+	}
+	return x
+}
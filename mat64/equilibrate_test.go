@@ -0,0 +1,169 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"errors"
+	check "launchpad.net/gocheck"
+	"math"
+)
+
+func (s *S) TestEquilibrate(c *check.C) {
+	for i, test := range []struct {
+		a    *Dense
+		pow2 bool
+		rows bool
+		cols bool
+	}{
+		{
+			a: NewDense(3, 3, []float64{
+				1e10, 2e10, 3e10,
+				4e-5, 5e-5, 6e-5,
+				7, 8, 10,
+			}),
+			pow2: true,
+			rows: true,
+		},
+		{
+			a: NewDense(3, 3, []float64{
+				1e10, 2e-5, 3,
+				4e10, 5e-5, 6,
+				7e10, 8e-5, 10,
+			}),
+			cols: true,
+		},
+		{
+			a: NewDense(3, 2, []float64{
+				3, -1,
+				2, 4,
+				-1, 2,
+			}),
+			pow2: true,
+		},
+	} {
+		e, err := Equilibrate(test.a, test.pow2)
+		c.Assert(err, check.Equals, nil, check.Commentf("Test %d", i))
+		rows, cols := e.Needed()
+		c.Check(rows, check.Equals, test.rows, check.Commentf("Test %d", i))
+		c.Check(cols, check.Equals, test.cols, check.Commentf("Test %d", i))
+
+		// Every row and column of the scaled matrix has its largest
+		// element in [0.5, 1] and, for power of two factors, the
+		// scaling is exact.
+		as := DenseCopyOf(test.a)
+		e.Scale(as, true, true)
+		m, n := as.Dims()
+		for r := 0; r < m; r++ {
+			var v float64
+			for j := 0; j < n; j++ {
+				v = math.Max(v, math.Abs(as.At(r, j)))
+			}
+			c.Check(v >= 0.5 && v <= 1, check.Equals, true, check.Commentf("Test %d row %d: %v", i, r, v))
+		}
+		for j := 0; j < n; j++ {
+			var v float64
+			for r := 0; r < m; r++ {
+				v = math.Max(v, math.Abs(as.At(r, j)))
+			}
+			c.Check(v >= 0.5 && v <= 1, check.Equals, true, check.Commentf("Test %d col %d: %v", i, j, v))
+		}
+		if test.pow2 {
+			for r := 0; r < m; r++ {
+				for j := 0; j < n; j++ {
+					c.Check(as.At(r, j)/(e.R[r]*e.C[j]), check.Equals, test.a.At(r, j))
+				}
+			}
+		}
+	}
+
+	_, err := Equilibrate(NewDense(2, 2, []float64{1, 0, 2, 0}), false)
+	c.Check(err, check.Equals, ErrSingular)
+	_, err = Equilibrate(NewDense(2, 2, []float64{1, 2, 0, 0}), true)
+	c.Check(err, check.Equals, ErrSingular)
+}
+
+func (s *S) TestLUEquilibrated(c *check.C) {
+	for i, test := range []struct {
+		a          *Dense
+		x          *Dense
+		rows, cols bool
+		tol        float64
+	}{
+		{
+			a: NewDense(3, 3, []float64{
+				1e-12, 2e-12, 3e-12,
+				4e8, 5e8, 6e8,
+				7, 8, 10,
+			}),
+			x:    NewDense(3, 1, []float64{1, -2, 3}),
+			rows: true,
+			tol:  1e-12,
+		},
+		{
+			a: NewDense(3, 3, []float64{
+				1e-10, 1, 1,
+				1e-10, 2, -1,
+				3e-10, 1, 4,
+			}),
+			x:    NewDense(3, 2, []float64{1e10, -2e10, 1, 2, 3, 4}),
+			cols: true,
+			tol:  1e-12,
+		},
+		{
+			a: NewDense(2, 2, []float64{
+				4, 1,
+				2, 3,
+			}),
+			x:   NewDense(2, 1, []float64{1, 2}),
+			tol: 1e-14,
+		},
+	} {
+		f := LUEquilibrated(DenseCopyOf(test.a))
+		c.Check(f.Rows, check.Equals, test.rows, check.Commentf("Test %d", i))
+		c.Check(f.Cols, check.Equals, test.cols, check.Commentf("Test %d", i))
+		c.Check(f.IsSingular(), check.Equals, false, check.Commentf("Test %d", i))
+
+		var b Dense
+		b.Mul(test.a, test.x)
+		x := f.Solve(&b)
+		c.Check(x.EqualsApprox(test.x, test.tol*test.x.Norm(0)), check.Equals, true, check.Commentf("Test %d: %v", i, x))
+
+		// The transposed systems need not be well conditioned, so check
+		// the residual.
+		b.Mul(test.a.T(), test.x)
+		want := DenseCopyOf(&b)
+		x = f.SolveTrans(&b)
+		var r Dense
+		r.Mul(test.a.T(), x)
+		c.Check(r.EqualsApprox(want, test.tol*want.Norm(0)), check.Equals, true, check.Commentf("Test %d: %v", i, &r))
+	}
+
+	f := LUEquilibrated(NewDense(2, 2, []float64{1, 0, 2, 0}))
+	c.Check(f.IsSingular(), check.Equals, true)
+	c.Check(func() { LUEquilibrated(NewDense(2, 3, nil)) }, check.PanicMatches, ".*expect square.*")
+}
+
+func (s *S) TestSolveEquilibrated(c *check.C) {
+	// Partial pivoting on the unscaled matrix picks the first row, whose
+	// large element swamps the second row and loses x[0] entirely.
+	a := NewDense(2, 2, []float64{
+		1, 1e20,
+		1, 1,
+	})
+	b := NewDense(2, 1, []float64{1e20, 2})
+	want := NewDense(2, 1, []float64{1, 1})
+
+	c.Check(Solve(a, b).EqualsApprox(want, 1e-2), check.Equals, false)
+	x := SolveEquilibrated(a, b)
+	c.Check(x.EqualsApprox(want, 1e-14), check.Equals, true, check.Commentf("%v", x))
+
+	var at Dense
+	at.TCopy(a)
+	x = SolveEquilibrated(at.T(), b)
+	c.Check(x.EqualsApprox(want, 1e-14), check.Equals, true, check.Commentf("%v", x))
+
+	_, err := TrySolveEquilibrated(NewDense(2, 2, []float64{1, 2, 2, 4}), b)
+	c.Check(errors.Is(err, ErrSingular), check.Equals, true)
+}
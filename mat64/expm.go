@@ -0,0 +1,400 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+)
+
+// Degrees, 1-norm bounds and coefficients of the diagonal Padé approximants
+// to the exponential used by Expm, from Higham, "The scaling and squaring
+// method for the matrix exponential revisited", SIAM J. Matrix Anal. Appl.
+// 26(4), 2005.
+var (
+	padeTheta = []float64{
+		1.495585217958292e-2,
+		2.539398330063230e-1,
+		9.504178996162932e-1,
+		2.097847961257068,
+		5.371920351148152,
+	}
+	padeCoef = [][]float64{
+		{120, 60, 12, 1},
+		{30240, 15120, 3360, 420, 30, 1},
+		{17297280, 8648640, 1995840, 277200, 25200, 1512, 56, 1},
+		{17643225600, 8821612800, 2075673600, 302702400, 30270240, 2162160, 110880, 3960, 90, 1},
+		{
+			64764752532480000, 32382376266240000, 7771770303897600, 1187353796428800,
+			129060195264000, 10559470521600, 670442572800, 33522128640,
+			1323241920, 40840800, 960960, 16380, 182, 1,
+		},
+	}
+)
+
+// logmNodes is the number of Gauss-Legendre nodes used by Logm, giving
+// the [8/8] Padé approximant to log(I+x).
+const logmNodes = 8
+
+// expmActionTerms is the maximum number of Taylor terms taken in each step
+// of ExpmAction.
+const expmActionTerms = 50
+
+// Expm returns the matrix exponential of the square matrix a computed by the
+// scaling and squaring method with a Padé approximant of degree 3, 5, 7, 9 or
+// 13 chosen from the 1-norm of a.
+func Expm(a Matrix) *Dense {
+	m, n := a.Dims()
+	if m != n {
+		panic(opError(ErrSquare, "Expm", a))
+	}
+
+	x := DenseCopyOf(a)
+	norm := norm1(x)
+	last := len(padeTheta) - 1
+	for i, theta := range padeTheta[:last] {
+		if norm <= theta {
+			return padeExp(x, padeCoef[i])
+		}
+	}
+
+	var s int
+	if norm > padeTheta[last] {
+		s = int(math.Ceil(math.Log2(norm / padeTheta[last])))
+		x.Scale(math.Ldexp(1, -s), x)
+	}
+	r := padeExp(x, padeCoef[last])
+	for ; s > 0; s-- {
+		r.Mul(r, r)
+	}
+	return r
+}
+
+// padeExp returns the diagonal Padé approximant to the exponential of a
+// with the coefficients b.
+func padeExp(a *Dense, b []float64) *Dense {
+	n, _ := a.Dims()
+
+	// Form the even powers of a needed for the odd part u and
+	// the even part v of the numerator.
+	want := len(b) / 2
+	if len(b) == 14 {
+		want = 4
+	}
+	a2 := &Dense{}
+	a2.Mul(a, a)
+	pow := []*Dense{newIdentity(n), a2}
+	for len(pow) < want {
+		p := &Dense{}
+		p.Mul(pow[len(pow)-1], a2)
+		pow = append(pow, p)
+	}
+
+	u := NewDense(n, n, nil)
+	v := NewDense(n, n, nil)
+	if len(b) == 14 {
+		// Evaluate the degree 13 approximant with the scheme of
+		// Higham, reusing a^6 to reduce the number of products.
+		a6 := pow[3]
+		for k := 1; k < 4; k++ {
+			addScaled(u, b[2*k+7], pow[k])
+			addScaled(v, b[2*k+6], pow[k])
+		}
+		u.Mul(a6, u)
+		v.Mul(a6, v)
+		for k := 0; k < 4; k++ {
+			addScaled(u, b[2*k+1], pow[k])
+			addScaled(v, b[2*k], pow[k])
+		}
+	} else {
+		for k, p := range pow {
+			addScaled(u, b[2*k+1], p)
+			addScaled(v, b[2*k], p)
+		}
+	}
+	u.Mul(a, u)
+
+	// Solve (v-u)*r = v+u.
+	num := &Dense{}
+	num.Add(v, u)
+	den := &Dense{}
+	den.Sub(v, u)
+	return LU(den).Solve(num)
+}
+
+// ExpmAction returns exp(t*a)*b for the square matrix a without forming the
+// exponential. The product is accumulated from truncated Taylor series over
+// steps small enough that t*a has a 1-norm of at most one within each step,
+// after shifting a by its mean eigenvalue.
+func ExpmAction(a Matrix, t float64, b Matrix) *Dense {
+	m, n := a.Dims()
+	if m != n {
+		panic(opError(ErrSquare, "ExpmAction", a, b))
+	}
+	if br, _ := b.Dims(); br != n {
+		panic(opError(ErrShape, "ExpmAction", a, b))
+	}
+
+	x := DenseCopyOf(a)
+	mu := x.Trace() / float64(n)
+	for i := 0; i < n; i++ {
+		x.Set(i, i, x.At(i, i)-mu)
+	}
+
+	s := max(1, int(math.Ceil(math.Abs(t)*norm1(x))))
+	h := t / float64(s)
+	eta := math.Exp(mu * h)
+
+	f := DenseCopyOf(b)
+	term := DenseCopyOf(b)
+	for i := 0; i < s; i++ {
+		c1 := normInf(term)
+		for j := 1; j <= expmActionTerms; j++ {
+			term.Mul(x, term)
+			term.Scale(h/float64(j), term)
+			c2 := normInf(term)
+			f.Add(f, term)
+			if c1+c2 <= epsilon*normInf(f) {
+				break
+			}
+			c1 = c2
+		}
+		f.Scale(eta, f)
+		term = DenseCopyOf(f)
+	}
+	return f
+}
+
+// Logm returns the principal logarithm of the square matrix a computed by
+// inverse scaling and squaring on the real Schur form of a. Square roots are
+// taken until the result is close to the identity, and its logarithm is then
+// formed from a Padé approximant evaluated by Gauss-Legendre quadrature.
+//
+// Logm returns ErrNegativeEigen if a has a negative real eigenvalue and
+// ErrSingular if a is singular, in which case no real principal logarithm exists.
+func Logm(a Matrix) (*Dense, error) {
+	m, n := a.Dims()
+	if m != n {
+		panic(opError(ErrSquare, "Logm", a))
+	}
+
+	// Eigenvalues within rounding error of zero are taken to be zero.
+	f := Schur(DenseCopyOf(a), epsilon)
+	tol := float64(n) * epsilon * norm1(f.T)
+	for i, re := range f.d {
+		if f.e[i] != 0 {
+			continue
+		}
+		if math.Abs(re) <= tol {
+			return nil, ErrSingular
+		}
+		if re < 0 {
+			return nil, ErrNegativeEigen
+		}
+	}
+
+	eye := newIdentity(n)
+	t := f.T
+	x := &Dense{}
+	var s int
+	for {
+		x.Sub(t, eye)
+		if norm1(x) <= 0.25 {
+			break
+		}
+		var err error
+		t, err = sqrtQuasi(t)
+		if err != nil {
+			return nil, err
+		}
+		s++
+	}
+
+	// log(I+x) is the integral of x*inv(I+u*x) for u over [0, 1].
+	nodes, weights := gaussLegendre(logmNodes)
+	l := NewDense(n, n, nil)
+	den := &Dense{}
+	for k, u := range nodes {
+		den.Scale(u, x)
+		den.Add(den, eye)
+		addScaled(l, weights[k], LU(DenseCopyOf(den)).Solve(DenseCopyOf(x)))
+	}
+	l.Scale(math.Ldexp(1, s), l)
+
+	return similarity(f.Z, l), nil
+}
+
+// Sqrtm returns the principal square root of the square matrix a computed
+// from the real Schur form of a by the method of Higham, "Computing real
+// square roots of a real matrix", Linear Algebra Appl. 88/89, 1987.
+//
+// Sqrtm returns ErrNegativeEigen if a has a negative real eigenvalue, and
+// ErrSingular if a is singular and has no square root, as for a nilpotent
+// Jordan block. A singular a whose zero eigenvalues are semisimple, such as the
+// zero matrix, has a square root and is handled.
+func Sqrtm(a Matrix) (*Dense, error) {
+	m, n := a.Dims()
+	if m != n {
+		panic(opError(ErrSquare, "Sqrtm", a))
+	}
+
+	f := Schur(DenseCopyOf(a), epsilon)
+	r, err := sqrtQuasi(f.T)
+	if err != nil {
+		return nil, err
+	}
+	return similarity(f.Z, r), nil
+}
+
+// sqrtQuasi returns the principal square root of the upper quasi-triangular
+// matrix t. The root is upper quasi-triangular with the same block structure
+// as t and is formed one block column at a time. Diagonal elements of t
+// within rounding error of zero are taken to be zero.
+func sqrtQuasi(t *Dense) (*Dense, error) {
+	n, _ := t.Dims()
+	r := NewDense(n, n, nil)
+	blk := schurBlocks(t)
+	tol := float64(n) * epsilon * norm1(t)
+
+	for jb := 0; jb < len(blk)-1; jb++ {
+		j, q := blk[jb], blk[jb+1]-blk[jb]
+
+		if q == 1 {
+			v := t.At(j, j)
+			switch {
+			case math.Abs(v) <= tol:
+				r.Set(j, j, 0)
+			case v < 0:
+				return nil, ErrNegativeEigen
+			default:
+				r.Set(j, j, math.Sqrt(v))
+			}
+		} else {
+			// For eigenvalues theta ± i*mu with principal square
+			// roots alpha ± i*beta, the root of the block is
+			// alpha*I + (t-theta*I)/(2*alpha).
+			theta, mu := blockEigen(t, j)
+			alpha := math.Sqrt((math.Hypot(theta, mu) + theta) / 2)
+			for k := j; k < j+2; k++ {
+				for l := j; l < j+2; l++ {
+					v := t.At(k, l)
+					if k == l {
+						v -= theta
+					}
+					r.Set(k, l, v/(2*alpha))
+				}
+				r.Set(k, k, r.At(k, k)+alpha)
+			}
+		}
+
+		// Solve r_ii*r_ij + r_ij*r_jj = t_ij - sum_k r_ik*r_kj for
+		// the blocks above the diagonal.
+		rjj := NewDense(q, q, nil)
+		rjj.Submatrix(r, j, j, q, q)
+		for ib := jb - 1; ib >= 0; ib-- {
+			i, p := blk[ib], blk[ib+1]-blk[ib]
+			c := NewDense(p, q, nil)
+			for k := 0; k < p; k++ {
+				for l := 0; l < q; l++ {
+					v := t.At(i+k, j+l)
+					for h := i + p; h < j; h++ {
+						v -= r.At(i+k, h) * r.At(h, j+l)
+					}
+					c.Set(k, l, v)
+				}
+			}
+			if p == 1 && q == 1 && math.Abs(t.At(i, i)) <= tol && math.Abs(t.At(j, j)) <= tol {
+				// Both eigenvalues are zero, so r_ij is free when
+				// its right hand side vanishes and does not exist
+				// otherwise.
+				if math.Abs(c.At(0, 0)) > tol {
+					return nil, ErrSingular
+				}
+				r.Set(i, j, 0)
+				continue
+			}
+			rii := NewDense(p, p, nil)
+			rii.Submatrix(r, i, i, p, p)
+			x, err := sylvesterBlock(rii, rjj, c)
+			if err != nil {
+				return nil, err
+			}
+			for k := 0; k < p; k++ {
+				for l := 0; l < q; l++ {
+					r.Set(i+k, j+l, x.At(k, l))
+				}
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// gaussLegendre returns the nodes and weights of the n-point Gauss-Legendre
+// quadrature rule on [0, 1], computed from the eigendecomposition of the
+// Jacobi matrix by the method of Golub and Welsch.
+func gaussLegendre(n int) (x, w []float64) {
+	j := NewDense(n, n, nil)
+	for k := 1; k < n; k++ {
+		b := float64(k) / math.Sqrt(float64(4*k*k-1))
+		j.Set(k, k-1, b)
+		j.Set(k-1, k, b)
+	}
+	ef := Eigen(j, epsilon)
+
+	x = make([]float64, n)
+	w = make([]float64, n)
+	for k := range x {
+		x[k] = (ef.d[k] + 1) / 2
+		w[k] = ef.V.At(0, k) * ef.V.At(0, k)
+	}
+	return x, w
+}
+
+// newIdentity returns a new n-by-n identity matrix.
+func newIdentity(n int) *Dense {
+	m := NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		m.Set(i, i, 1)
+	}
+	return m
+}
+
+// addScaled adds f*a to m.
+func addScaled(m *Dense, f float64, a *Dense) {
+	r, c := a.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.Set(i, j, m.At(i, j)+f*a.At(i, j))
+		}
+	}
+}
+
+// norm1 returns the maximum absolute column sum of a.
+func norm1(a *Dense) float64 {
+	r, c := a.Dims()
+	var n float64
+	for j := 0; j < c; j++ {
+		var s float64
+		for i := 0; i < r; i++ {
+			s += math.Abs(a.At(i, j))
+		}
+		n = math.Max(n, s)
+	}
+	return n
+}
+
+// normInf returns the maximum absolute row sum of a.
+func normInf(a *Dense) float64 {
+	r, c := a.Dims()
+	var n float64
+	for i := 0; i < r; i++ {
+		var s float64
+		for j := 0; j < c; j++ {
+			s += math.Abs(a.At(i, j))
+		}
+		n = math.Max(n, s)
+	}
+	return n
+}
@@ -0,0 +1,242 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+)
+
+// rotation returns the 2-by-2 rotation through theta.
+func rotation(theta float64) *Dense {
+	s, c := math.Sincos(theta)
+	return NewDense(2, 2, []float64{c, -s, s, c})
+}
+
+func (s *S) TestExpm(c *check.C) {
+	for i, test := range []struct {
+		a, want *Dense
+	}{
+		{
+			a:    NewDense(3, 3, []float64{1, 0, 0, 0, -2, 0, 0, 0, 0.5}),
+			want: NewDense(3, 3, []float64{math.E, 0, 0, 0, math.Exp(-2), 0, 0, 0, math.Exp(0.5)}),
+		},
+		{
+			a:    NewDense(2, 2, []float64{0, -0.01, 0.01, 0}),
+			want: rotation(0.01),
+		},
+		{
+			a:    NewDense(2, 2, []float64{0, -0.5, 0.5, 0}),
+			want: rotation(0.5),
+		},
+		{
+			a:    NewDense(2, 2, []float64{0, -10, 10, 0}),
+			want: rotation(10),
+		},
+		{
+			// exp of a Jordan block is e^λ*(I + N + N²/2).
+			a: NewDense(3, 3, []float64{
+				2, 3, 0,
+				0, 2, 3,
+				0, 0, 2,
+			}),
+			want: NewDense(3, 3, []float64{
+				math.Exp(2), 3 * math.Exp(2), 4.5 * math.Exp(2),
+				0, math.Exp(2), 3 * math.Exp(2),
+				0, 0, math.Exp(2),
+			}),
+		},
+	} {
+		got := Expm(test.a)
+		var d Dense
+		d.Sub(got, test.want)
+		c.Check(d.Norm(0) <= 1e-14*test.want.Norm(0), check.Equals, true,
+			check.Commentf("Test %d: error %v", i, d.Norm(0)))
+	}
+
+	c.Check(func() { Expm(NewDense(2, 3, nil)) }, check.PanicMatches, ".*expect square.*")
+}
+
+func (s *S) TestExpmAction(c *check.C) {
+	a := NewDense(3, 3, []float64{
+		-2, 1, 0.5,
+		0.3, -1, 2,
+		1, 0, -4,
+	})
+	b := NewDense(3, 2, []float64{1, 0, -1, 2, 0.5, 1})
+	for i, t := range []float64{0, 0.1, 1, -2, 7.5} {
+		var ta, want Dense
+		ta.Scale(t, a)
+		want.Mul(Expm(&ta), b)
+		got := ExpmAction(a, t, b)
+		var d Dense
+		d.Sub(got, &want)
+		c.Check(d.Norm(0) <= 1e-12*want.Norm(0), check.Equals, true,
+			check.Commentf("Test %d: error %v", i, d.Norm(0)))
+	}
+}
+
+func (s *S) TestLogm(c *check.C) {
+	for i, test := range []struct {
+		a, want *Dense
+		err     error
+	}{
+		{
+			a:    NewDense(2, 2, []float64{math.E, 0, 0, 4}),
+			want: NewDense(2, 2, []float64{1, 0, 0, math.Log(4)}),
+		},
+		{
+			a:    rotation(2.5),
+			want: NewDense(2, 2, []float64{0, -2.5, 2.5, 0}),
+		},
+		{
+			a: NewDense(3, 3, []float64{
+				1, 2, 0,
+				0, 1, 2,
+				0, 0, 1,
+			}),
+			// log(I+N) = N - N²/2.
+			want: NewDense(3, 3, []float64{
+				0, 2, -2,
+				0, 0, 2,
+				0, 0, 0,
+			}),
+		},
+		{
+			a:   NewDense(2, 2, []float64{-1, 0, 0, 1}),
+			err: ErrNegativeEigen,
+		},
+		{
+			a:   NewDense(2, 2, []float64{1, 1, 1, 1}),
+			err: ErrSingular,
+		},
+		{
+			// Zero eigenvalues may be rounded to small negative values.
+			a: NewDense(3, 3, []float64{
+				1, 2, 3,
+				2, 4, 6,
+				3, 6, 9,
+			}),
+			err: ErrSingular,
+		},
+	} {
+		got, err := Logm(test.a)
+		c.Check(err, check.Equals, test.err, check.Commentf("Test %d", i))
+		if test.err != nil {
+			continue
+		}
+		var d Dense
+		d.Sub(got, test.want)
+		c.Check(d.Norm(0) <= 1e-13*test.want.Norm(0), check.Equals, true,
+			check.Commentf("Test %d: error %v", i, d.Norm(0)))
+	}
+
+	// log(exp(a)) = a for a with eigenvalues of imaginary part in (-π, π).
+	a := NewDense(4, 4, []float64{
+		0.1, -1, 0.2, 0,
+		1, 0.1, 0, 0.3,
+		0, 0.5, -0.4, 0.2,
+		0.2, 0, 0.1, 0.6,
+	})
+	got, err := Logm(Expm(a))
+	c.Assert(err, check.Equals, nil)
+	var d Dense
+	d.Sub(got, a)
+	c.Check(d.Norm(0) <= 1e-13*a.Norm(0), check.Equals, true, check.Commentf("error %v", d.Norm(0)))
+}
+
+func (s *S) TestSqrtm(c *check.C) {
+	for i, test := range []struct {
+		a, want *Dense
+		err     error
+	}{
+		{
+			a:    NewDense(2, 2, []float64{4, 0, 0, 9}),
+			want: NewDense(2, 2, []float64{2, 0, 0, 3}),
+		},
+		{
+			a:    rotation(2),
+			want: rotation(1),
+		},
+		{
+			a:    NewDense(2, 2, []float64{4, 1, 0, 4}),
+			want: NewDense(2, 2, []float64{2, 0.25, 0, 2}),
+		},
+		{
+			a: NewDense(3, 3, []float64{
+				1, 2, 3,
+				0, 4, 5,
+				0, 0, 9,
+			}),
+			want: NewDense(3, 3, []float64{
+				1, 2. / 3, 7. / 12,
+				0, 2, 1,
+				0, 0, 3,
+			}),
+		},
+		{
+			a:   NewDense(2, 2, []float64{1, 2, 0, -1}),
+			err: ErrNegativeEigen,
+		},
+		{
+			a:    NewDense(2, 2, nil),
+			want: NewDense(2, 2, nil),
+		},
+		{
+			a: NewDense(3, 3, []float64{
+				0, 0, 1,
+				0, 0, 2,
+				0, 0, 4,
+			}),
+			want: NewDense(3, 3, []float64{
+				0, 0, 0.5,
+				0, 0, 1,
+				0, 0, 2,
+			}),
+		},
+		{
+			a:   NewDense(2, 2, []float64{0, 1, 0, 0}),
+			err: ErrSingular,
+		},
+		{
+			// The rank one positive semidefinite v*v' has the root
+			// v*v'/||v||, though its zero eigenvalues may be rounded
+			// to small negative values.
+			a: NewDense(3, 3, []float64{
+				1, 2, 3,
+				2, 4, 6,
+				3, 6, 9,
+			}),
+			want: NewDense(3, 3, []float64{
+				1 / math.Sqrt(14), 2 / math.Sqrt(14), 3 / math.Sqrt(14),
+				2 / math.Sqrt(14), 4 / math.Sqrt(14), 6 / math.Sqrt(14),
+				3 / math.Sqrt(14), 6 / math.Sqrt(14), 9 / math.Sqrt(14),
+			}),
+		},
+	} {
+		got, err := Sqrtm(test.a)
+		c.Check(err, check.Equals, test.err, check.Commentf("Test %d", i))
+		if test.err != nil {
+			continue
+		}
+		var d Dense
+		d.Sub(got, test.want)
+		c.Check(d.Norm(0) <= 1e-14*test.want.Norm(0), check.Equals, true,
+			check.Commentf("Test %d: error %v", i, d.Norm(0)))
+	}
+
+	// The square root of a matrix with complex eigenvalues squares back to it.
+	a := NewDense(4, 4, []float64{
+		3, -1, 0.5, 0.2,
+		2, 3, 0, 0.1,
+		0.1, 0.4, 2, 0,
+		0, 0.3, 0.2, 5,
+	})
+	r, err := Sqrtm(a)
+	c.Assert(err, check.Equals, nil)
+	var sq Dense
+	sq.Mul(r, r)
+	c.Check(sq.EqualsApprox(a, 1e-13), check.Equals, true)
+}
@@ -0,0 +1,364 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Funm returns f(a) for the square matrix a and a function f that is analytic
+// on the spectrum of a and satisfies f(conj(z)) = conj(f(z)), such as cmplx.Sin
+// or cmplx.Cos. If a is symmetric, f is applied to the eigenvalues of a;
+// otherwise f(a) is computed from the real Schur form of a by the blocked
+// Schur-Parlett method of Davies and Higham. The Schur form is reordered so
+// that eigenvalues within funmDelta of each other, directly or through a chain
+// of such eigenvalues, lie in one diagonal block. f of each block holding a
+// single eigenvalue or conjugate pair is evaluated directly, and f of the other
+// blocks by the trapezoidal rule for the Cauchy integral on a circle about the
+// block's eigenvalues, so f must also be analytic on the disc of radius
+// max(2*spread, funmDelta) about their mean. The blocks above the diagonal are
+// then found by the Parlett recurrence, which is well conditioned because the
+// eigenvalues of different blocks are separated.
+//
+// Funm returns ErrRepeatedEigen if the blocks of the Schur form cannot be
+// separated in rounding arithmetic.
+func Funm(f func(complex128) complex128, a Matrix) (*Dense, error) {
+	m, n := a.Dims()
+	if m != n {
+		panic(opError(ErrSquare, "Funm", a))
+	}
+
+	x := DenseCopyOf(a)
+	if symmetric(x) {
+		ef := Eigen(x, epsilon)
+		d := NewDense(n, n, nil)
+		for i, v := range ef.d {
+			d.Set(i, i, real(f(complex(v, 0))))
+		}
+		return similarity(ef.V, d), nil
+	}
+
+	sf := Schur(x, epsilon)
+	blk, err := clusterSchur(sf.T, sf.Z)
+	if err != nil {
+		return nil, ErrRepeatedEigen
+	}
+	ft, err := funmQuasi(f, sf.T, blk)
+	if err != nil {
+		return nil, err
+	}
+	return similarity(sf.Z, ft), nil
+}
+
+// funmDelta is the distance within which eigenvalues are placed in the same
+// diagonal block by Funm, as suggested by Davies and Higham.
+const funmDelta = 0.1
+
+// clusterSchur reorders the real Schur decomposition with quasi-triangular t
+// and orthogonal z so that the diagonal blocks of t whose eigenvalues are
+// linked by a chain of eigenvalues no further than funmDelta apart are
+// contiguous. The clusters keep the order of their first block in t. It returns
+// the starting indices of the clusters, followed by the order of t.
+func clusterSchur(t, z *Dense) ([]int, error) {
+	blk := schurBlocks(t)
+	nb := len(blk) - 1
+	size := make([]int, nb)
+	re := make([]float64, nb)
+	im := make([]float64, nb)
+	for b := range size {
+		size[b] = blk[b+1] - blk[b]
+		re[b], im[b] = blockValue(t, blk[b], size[b])
+	}
+
+	// Join the blocks into clusters, labelled by their first block.
+	label := make([]int, nb)
+	for b := range label {
+		label[b] = b
+	}
+	for b := 1; b < nb; b++ {
+		for c := 0; c < b; c++ {
+			if label[c] == label[b] || math.Hypot(re[b]-re[c], im[b]-im[c]) > funmDelta {
+				continue
+			}
+			from, to := label[c], label[b]
+			if from < to {
+				from, to = to, from
+			}
+			for k := range label {
+				if label[k] == from {
+					label[k] = to
+				}
+			}
+		}
+	}
+
+	// Bring each cluster together by swapping adjacent blocks of t, as in an
+	// insertion sort on the labels.
+	for b := 1; b < nb; b++ {
+		for k := b; k > 0 && label[k-1] > label[k]; k-- {
+			var j int
+			for _, p := range size[:k-1] {
+				j += p
+			}
+			err := swapSchurBlocks(t, z, j, size[k-1], size[k])
+			if err != nil {
+				return nil, err
+			}
+			size[k-1], size[k] = size[k], size[k-1]
+			label[k-1], label[k] = label[k], label[k-1]
+		}
+	}
+
+	var clusters []int
+	var j int
+	for b := range label {
+		if b == 0 || label[b] != label[b-1] {
+			clusters = append(clusters, j)
+		}
+		j += size[b]
+	}
+	return append(clusters, j), nil
+}
+
+// funmQuasi returns f(t) for the upper quasi-triangular matrix t with
+// diagonal blocks starting at the indices in blk, followed by the order of t.
+// The diagonal blocks are evaluated by funmBlock and the blocks above them are
+// found one block column at a time from t*f(t) = f(t)*t.
+func funmQuasi(f func(complex128) complex128, t *Dense, blk []int) (*Dense, error) {
+	n, _ := t.Dims()
+	r := NewDense(n, n, nil)
+
+	for jb := 0; jb < len(blk)-1; jb++ {
+		j, q := blk[jb], blk[jb+1]-blk[jb]
+
+		tjj := &Dense{}
+		tjj.Submatrix(t, j, j, q, q)
+		fjj := funmBlock(f, tjj)
+		for k := 0; k < q; k++ {
+			for l := 0; l < q; l++ {
+				r.Set(j+k, j+l, fjj.At(k, l))
+			}
+		}
+
+		// Solve t_ii*f_ij - f_ij*t_jj = f_ii*t_ij - t_ij*f_jj +
+		// sum_k (f_ik*t_kj - t_ik*f_kj) for the blocks above the
+		// diagonal.
+		tjj.Scale(-1, tjj)
+		for ib := jb - 1; ib >= 0; ib-- {
+			i, p := blk[ib], blk[ib+1]-blk[ib]
+			c := NewDense(p, q, nil)
+			for k := 0; k < p; k++ {
+				for l := 0; l < q; l++ {
+					var v float64
+					for h := i; h < j; h++ {
+						v += r.At(i+k, h) * t.At(h, j+l)
+					}
+					for h := i + p; h < j+q; h++ {
+						v -= t.At(i+k, h) * r.At(h, j+l)
+					}
+					c.Set(k, l, v)
+				}
+			}
+			tii := &Dense{}
+			tii.Submatrix(t, i, i, p, p)
+			x, err := sylvesterQuasi(tii, tjj, c)
+			if err != nil {
+				return nil, ErrRepeatedEigen
+			}
+			for k := 0; k < p; k++ {
+				for l := 0; l < q; l++ {
+					r.Set(i+k, j+l, x.At(k, l))
+				}
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// funmBlock returns f(t) for a diagonal block t of a clustered real Schur
+// form. A 1-by-1 block is evaluated directly and a 2-by-2 block holding a
+// conjugate pair theta ± i*mu as re(f(lambda))*I + im(f(lambda))/mu*(t-theta*I).
+// Larger blocks are evaluated by funmContour.
+func funmBlock(f func(complex128) complex128, t *Dense) *Dense {
+	n, _ := t.Dims()
+	switch {
+	case n == 1:
+		return NewDense(1, 1, []float64{real(f(complex(t.At(0, 0), 0)))})
+	case n == 2 && t.At(1, 0) != 0:
+		theta, mu := blockEigen(t, 0)
+		fl := f(complex(theta, mu))
+		r := NewDense(2, 2, nil)
+		for k := 0; k < 2; k++ {
+			for l := 0; l < 2; l++ {
+				v := t.At(k, l)
+				if k == l {
+					v -= theta
+				}
+				r.Set(k, l, imag(fl)/mu*v)
+			}
+			r.Set(k, k, r.At(k, k)+real(fl))
+		}
+		return r
+	}
+	return funmContour(f, t)
+}
+
+// funmContour returns f(t) for the upper quasi-triangular t whose eigenvalues
+// are clustered, approximating the Cauchy integral
+//  f(t) = 1/(2*pi*i) ∮ f(z)*(z*I-t)^-1 dz
+// by the trapezoidal rule on a circle about the mean sigma of the eigenvalues.
+// With the radius at least twice the distance of the furthest eigenvalue from
+// sigma, the error of the rule decreases geometrically in the number of nodes
+// and vanishes for the nilpotent part of t-sigma*I when there are more nodes
+// than rows of t. The conjugate symmetry of f makes the integral real.
+func funmContour(f func(complex128) complex128, t *Dense) *Dense {
+	n, _ := t.Dims()
+	blk := schurBlocks(t)
+	var sigma float64
+	for i := 0; i < n; i++ {
+		sigma += t.At(i, i)
+	}
+	sigma /= float64(n)
+	var spread float64
+	for b := 0; b < len(blk)-1; b++ {
+		re, im := blockValue(t, blk[b], blk[b+1]-blk[b])
+		spread = math.Max(spread, math.Hypot(re-sigma, im))
+	}
+	rad := math.Max(2*spread, funmDelta)
+	nodes := max(64, 8*n)
+
+	sum := make([]complex128, n*n)
+	res := make([]complex128, n*n)
+	for k := 0; k < nodes; k++ {
+		d := complex(rad, 0) * cmplx.Exp(complex(0, 2*math.Pi*(float64(k)+0.5)/float64(nodes)))
+		z := complex(sigma, 0) + d
+		resolvent(res, t, z)
+		w := f(z) * d / complex(float64(nodes), 0)
+		for i, v := range res {
+			sum[i] += w * v
+		}
+	}
+
+	r := NewDense(n, n, nil)
+	for i, v := range sum {
+		r.mat.Data[i/n*r.mat.Stride+i%n] = real(v)
+	}
+	return r
+}
+
+// resolvent places (z*I-t)^-1 for the small real matrix t in res, row-major,
+// by Gauss-Jordan elimination with partial pivoting in complex arithmetic.
+func resolvent(res []complex128, t *Dense, z complex128) {
+	n, _ := t.Dims()
+	a := make([]complex128, n*n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			a[i*n+j] = complex(-t.At(i, j), 0)
+			res[i*n+j] = 0
+		}
+		a[i*n+i] += z
+		res[i*n+i] = 1
+	}
+	for j := 0; j < n; j++ {
+		piv := j
+		for i := j + 1; i < n; i++ {
+			if cmplx.Abs(a[i*n+j]) > cmplx.Abs(a[piv*n+j]) {
+				piv = i
+			}
+		}
+		for k := 0; k < n; k++ {
+			a[j*n+k], a[piv*n+k] = a[piv*n+k], a[j*n+k]
+			res[j*n+k], res[piv*n+k] = res[piv*n+k], res[j*n+k]
+		}
+		inv := 1 / a[j*n+j]
+		for k := 0; k < n; k++ {
+			a[j*n+k] *= inv
+			res[j*n+k] *= inv
+		}
+		for i := 0; i < n; i++ {
+			if i == j || a[i*n+j] == 0 {
+				continue
+			}
+			m := a[i*n+j]
+			for k := 0; k < n; k++ {
+				a[i*n+k] -= m * a[j*n+k]
+				res[i*n+k] -= m * res[j*n+k]
+			}
+		}
+	}
+}
+
+// Pow returns a raised to the power p for the square matrix a. Integer powers
+// are formed by repeated squaring, inverting a first when p is negative. For
+// other p the integer part is formed by repeated squaring and the fractional
+// part f as Expm(f*Logm(a)). If p is not finite the power is formed as
+// Expm(p*Logm(a)).
+//
+// Pow returns ErrSingular if a is singular and p is negative or not an
+// integer, and ErrNegativeEigen if p is not an integer and a has a negative
+// real eigenvalue.
+func Pow(a Matrix, p float64) (*Dense, error) {
+	m, n := a.Dims()
+	if m != n {
+		panic(opError(ErrSquare, "Pow", a))
+	}
+
+	if math.IsInf(p, 0) || math.IsNaN(p) {
+		l, err := Logm(a)
+		if err != nil {
+			return nil, err
+		}
+		l.Scale(p, l)
+		return Expm(l), nil
+	}
+
+	x := DenseCopyOf(a)
+	k, frac := math.Modf(p)
+	if frac < 0 {
+		k--
+		frac++
+	}
+	if k < 0 {
+		lu := LU(DenseCopyOf(x))
+		if lu.IsSingular() {
+			return nil, ErrSingular
+		}
+		x = lu.Solve(newIdentity(n))
+		k = -k
+	}
+	r := powInt(x, k)
+	if frac == 0 {
+		return r, nil
+	}
+
+	l, err := Logm(a)
+	if err != nil {
+		return nil, err
+	}
+	l.Scale(frac, l)
+	r.Mul(r, Expm(l))
+	return r, nil
+}
+
+// powInt returns a^k by repeated squaring for a non-negative integer k. The
+// exponent is halved as a float64, which is exact for integers, so that k may
+// exceed the range of the integer types.
+func powInt(a *Dense, k float64) *Dense {
+	n, _ := a.Dims()
+	r := newIdentity(n)
+	sq := DenseCopyOf(a)
+	for k > 0 {
+		if math.Mod(k, 2) == 1 {
+			r.Mul(r, sq)
+		}
+		k = math.Floor(k / 2)
+		if k > 0 {
+			sq.Mul(sq, sq)
+		}
+	}
+	return r
+}
@@ -0,0 +1,189 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+	"math/cmplx"
+)
+
+func (s *S) TestFunm(c *check.C) {
+	theta := 0.7
+	for i, test := range []struct {
+		f       func(complex128) complex128
+		a, want *Dense
+		tol     float64
+		err     error
+	}{
+		{
+			f:    cmplx.Sin,
+			a:    NewDense(2, 2, []float64{0, theta, theta, 0}),
+			want: NewDense(2, 2, []float64{0, math.Sin(theta), math.Sin(theta), 0}),
+		},
+		{
+			f:    cmplx.Cos,
+			a:    NewDense(2, 2, []float64{0, theta, theta, 0}),
+			want: NewDense(2, 2, []float64{math.Cos(theta), 0, 0, math.Cos(theta)}),
+		},
+		{
+			// sin and cos of a rotation generator are hyperbolic.
+			f:    cmplx.Sin,
+			a:    NewDense(2, 2, []float64{0, -theta, theta, 0}),
+			want: NewDense(2, 2, []float64{0, -math.Sinh(theta), math.Sinh(theta), 0}),
+		},
+		{
+			f:    cmplx.Cos,
+			a:    NewDense(2, 2, []float64{0, -theta, theta, 0}),
+			want: NewDense(2, 2, []float64{math.Cosh(theta), 0, 0, math.Cosh(theta)}),
+		},
+		{
+			f: cmplx.Exp,
+			a: NewDense(3, 3, []float64{
+				1, 2, 3,
+				0, -1, 4,
+				0, 0, 2,
+			}),
+			want: Expm(NewDense(3, 3, []float64{
+				1, 2, 3,
+				0, -1, 4,
+				0, 0, 2,
+			})),
+		},
+		{
+			// f of a Jordan block holds the derivatives of f.
+			f:    cmplx.Exp,
+			a:    NewDense(2, 2, []float64{2, 1, 0, 2}),
+			want: NewDense(2, 2, []float64{math.Exp(2), math.Exp(2), 0, math.Exp(2)}),
+		},
+		{
+			f: cmplx.Sin,
+			a: NewDense(3, 3, []float64{
+				theta, 1, 0,
+				0, theta, 1,
+				0, 0, theta,
+			}),
+			want: NewDense(3, 3, []float64{
+				math.Sin(theta), math.Cos(theta), -math.Sin(theta) / 2,
+				0, math.Sin(theta), math.Cos(theta),
+				0, 0, math.Sin(theta),
+			}),
+		},
+		{
+			// Close and repeated eigenvalues are clustered apart from
+			// the distant one.
+			f: cmplx.Exp,
+			a: NewDense(4, 4, []float64{
+				1, 3, -1, 2,
+				0, 3, 2, 1,
+				0, 0, 1.05, 4,
+				0, 0, 0, 1,
+			}),
+			want: Expm(NewDense(4, 4, []float64{
+				1, 3, -1, 2,
+				0, 3, 2, 1,
+				0, 0, 1.05, 4,
+				0, 0, 0, 1,
+			})),
+			tol: 1e-13,
+		},
+		{
+			// A repeated conjugate pair.
+			f: cmplx.Exp,
+			a: NewDense(4, 4, []float64{
+				0, -theta, 1, 0.5,
+				theta, 0, 0, 1,
+				0, 0, 0, -theta,
+				0, 0, theta, 0,
+			}),
+			want: Expm(NewDense(4, 4, []float64{
+				0, -theta, 1, 0.5,
+				theta, 0, 0, 1,
+				0, 0, 0, -theta,
+				0, 0, theta, 0,
+			})),
+		},
+	} {
+		got, err := Funm(test.f, test.a)
+		c.Check(err, check.Equals, test.err, check.Commentf("Test %d", i))
+		if test.err != nil {
+			continue
+		}
+		tol := test.tol
+		if tol == 0 {
+			tol = 1e-14
+		}
+		var d Dense
+		d.Sub(got, test.want)
+		c.Check(d.Norm(0) <= tol*test.want.Norm(0), check.Equals, true,
+			check.Commentf("Test %d: error %v", i, d.Norm(0)))
+	}
+
+	// Funm agrees with Expm on a general matrix with complex eigenvalues.
+	a := NewDense(4, 4, []float64{
+		0.5, -1, 0.2, 0.1,
+		1, 0.3, 0, 0.2,
+		0.1, 0.4, -0.6, 0.3,
+		0.2, 0, 0.5, 0.9,
+	})
+	got, err := Funm(cmplx.Exp, a)
+	c.Assert(err, check.Equals, nil)
+	c.Check(got.EqualsApprox(Expm(a), 1e-13), check.Equals, true)
+}
+
+func (s *S) TestPow(c *check.C) {
+	a := NewDense(3, 3, []float64{
+		2, 1, 0,
+		0.5, 3, 1,
+		0, 0.2, 1,
+	})
+	a2 := &Dense{}
+	a2.Mul(a, a)
+	a5 := &Dense{}
+	a5.Mul(a2, a2)
+	a5.Mul(a5, a)
+	ainv2 := Inverse(a2)
+	sqrt, err := Sqrtm(a)
+	c.Assert(err, check.Equals, nil)
+	a15 := &Dense{}
+	a15.Mul(a, sqrt)
+
+	for i, test := range []struct {
+		a    Matrix
+		p    float64
+		want *Dense
+		err  error
+	}{
+		{a: a, p: 0, want: eye()},
+		{a: a, p: 1, want: a},
+		{a: a, p: 5, want: a5},
+		{a: a, p: -2, want: ainv2},
+		{a: a, p: 0.5, want: sqrt},
+		{a: a, p: 1.5, want: a15},
+		{a: rotation(2), p: 0.25, want: rotation(0.5)},
+		{a: rotation(2), p: -0.5, want: rotation(-1)},
+		{a: NewDense(2, 2, []float64{4, 0, 0, 9}), p: -1.5, want: NewDense(2, 2, []float64{1. / 8, 0, 0, 1. / 27})},
+		{a: NewDense(2, 2, []float64{1, 1, 1, 1}), p: -1, err: ErrSingular},
+		{a: NewDense(2, 2, []float64{-1, 0, 0, 1}), p: 0.5, err: ErrNegativeEigen},
+		{a: eye(), p: 1e19, want: eye()},
+		{a: eye(), p: -1e300, want: eye()},
+		{a: NewDense(3, 3, []float64{0.5, 0, 0, 0, 0.5, 0, 0, 0, 0.5}), p: 1e19, want: NewDense(3, 3, nil)},
+		// Huge integer powers of singular matrices and of matrices with
+		// negative eigenvalues are formed by repeated squaring.
+		{a: NewDense(2, 2, []float64{0.5, 0.5, 0.5, 0.5}), p: 1e20, want: NewDense(2, 2, []float64{0.5, 0.5, 0.5, 0.5})},
+		{a: NewDense(2, 2, []float64{-1, 0, 0, 1}), p: 1e20, want: NewDense(2, 2, []float64{1, 0, 0, 1})},
+		{a: NewDense(2, 2, []float64{1, 1, 1, 1}), p: -1e20, err: ErrSingular},
+	} {
+		got, err := Pow(test.a, test.p)
+		c.Check(err, check.Equals, test.err, check.Commentf("Test %d", i))
+		if test.err != nil {
+			continue
+		}
+		var d Dense
+		d.Sub(got, test.want)
+		c.Check(d.Norm(0) <= 1e-13*test.want.Norm(0), check.Equals, true,
+			check.Commentf("Test %d: error %v", i, d.Norm(0)))
+	}
+}
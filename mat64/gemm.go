@@ -0,0 +1,180 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"github.com/gonum/blas"
+)
+
+// transposeIf returns a transposed view of a if trans is true and a otherwise.
+// A Transpose is unwrapped rather than wrapped a second time.
+func transposeIf(a Matrix, trans bool) Matrix {
+	if !trans {
+		return a
+	}
+	if t, ok := a.(Transpose); ok {
+		return t.Matrix
+	}
+	return Transpose{a}
+}
+
+// MulTrans takes the matrix product of a and b, transposing a if aTrans is true
+// and b if bTrans is true, and places the result in the receiver. Transposed
+// operands are passed to BLAS as transposed rather than copied.
+func (m *Dense) MulTrans(a Matrix, aTrans bool, b Matrix, bTrans bool) {
+	m.Mul(transposeIf(a, aTrans), transposeIf(b, bTrans))
+}
+
+// Gemm performs the general matrix multiply and accumulate
+//  m = alpha*op(a)*op(b) + beta*m
+// where op(a) is a' if aTrans is true and a otherwise, and similarly for b.
+// If the receiver is zero it is allocated and beta is ignored. When a and b
+// both implement RawMatrixer and do not share the receiver's storage, the
+// update is a single Dgemm call.
+func (m *Dense) Gemm(alpha float64, a Matrix, aTrans bool, b Matrix, bTrans bool, beta float64) {
+	a, b = transposeIf(a, aTrans), transposeIf(b, bTrans)
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+
+	if ac != br {
+		panic(opError(ErrShape, "Dense.Gemm", m, a, b))
+	}
+
+	if m.isZero() {
+		m.mat = RawMatrix{
+			Order:  BlasOrder,
+			Rows:   ar,
+			Cols:   bc,
+			Stride: bc,
+			Data:   use(m.mat.Data, ar*bc),
+		}
+		beta = 0
+	} else if ar != m.mat.Rows || bc != m.mat.Cols {
+		panic(opError(ErrShape, "Dense.Gemm", m, a, b))
+	}
+
+	au, aT := untranspose(a)
+	bu, bT := untranspose(b)
+	if au, ok := au.(RawMatrixer); ok {
+		if bu, ok := bu.(RawMatrixer); ok {
+			amat, bmat := au.RawMatrix(), bu.RawMatrix()
+			if !rawOverlaps(m.mat, amat) && !rawOverlaps(m.mat, bmat) {
+				if blasEngine == nil {
+					panic(ErrNoEngine)
+				}
+				blasEngine.Dgemm(
+					BlasOrder,
+					aT, bT,
+					ar, bc, ac,
+					alpha,
+					amat.Data, amat.Stride,
+					bmat.Data, bmat.Stride,
+					beta,
+					m.mat.Data, m.mat.Stride)
+				return
+			}
+		}
+	}
+
+	var p Dense
+	p.Mul(a, b)
+	m.accumulate(alpha, &p, beta)
+}
+
+// SymRankK performs the symmetric rank-k update
+//  m = alpha*a*a' + beta*m
+// or m = alpha*a'*a + beta*m if trans is true. Only the upper triangle of the
+// receiver is read when beta is non-zero, so it should hold a symmetric matrix;
+// both triangles of the result are set. If the receiver is zero it is
+// allocated and beta is ignored. When a implements RawMatrixer and does not
+// share the receiver's storage, the update is a single Dsyrk call.
+func (m *Dense) SymRankK(alpha float64, a Matrix, trans bool, beta float64) {
+	a = transposeIf(a, trans)
+	n, k := a.Dims()
+
+	if m.isZero() {
+		m.mat = RawMatrix{
+			Order:  BlasOrder,
+			Rows:   n,
+			Cols:   n,
+			Stride: n,
+			Data:   use(m.mat.Data, n*n),
+		}
+		beta = 0
+	} else if n != m.mat.Rows || n != m.mat.Cols {
+		panic(opError(ErrShape, "Dense.SymRankK", m, a))
+	}
+
+	au, t := untranspose(a)
+	var amat RawMatrix
+	raw, ok := au.(RawMatrixer)
+	if ok {
+		amat = raw.RawMatrix()
+	}
+	if ok && !rawOverlaps(m.mat, amat) {
+		if blasEngine == nil {
+			panic(ErrNoEngine)
+		}
+		blasEngine.Dsyrk(
+			BlasOrder, blas.Upper, t,
+			n, k,
+			alpha,
+			amat.Data, amat.Stride,
+			beta,
+			m.mat.Data, m.mat.Stride)
+	} else {
+		// Only the upper triangle is updated, as by Dsyrk, and mirrored below.
+		var p Dense
+		p.Mul(a, transposeIf(a, true))
+		for i := 0; i < n; i++ {
+			row, prow := m.rowView(i), p.rowView(i)
+			for j := i; j < n; j++ {
+				if beta == 0 {
+					row[j] = alpha * prow[j]
+				} else {
+					row[j] = alpha*prow[j] + beta*row[j]
+				}
+			}
+		}
+	}
+
+	for i := 1; i < n; i++ {
+		for j := 0; j < i; j++ {
+			m.mat.Data[i*m.mat.Stride+j] = m.mat.Data[j*m.mat.Stride+i]
+		}
+	}
+}
+
+// rawOverlaps returns whether the elements of the matrices a and b may share
+// storage. Each matrix is treated as the span from its first to its last
+// element, so blocks interleaved within one backing array are reported as
+// overlapping.
+func rawOverlaps(a, b RawMatrix) bool {
+	return overlaps(a.Data, 1, rawSpan(a), b.Data, 1, rawSpan(b))
+}
+
+// rawSpan returns the number of elements from the first to the last element
+// of the matrix a.
+func rawSpan(a RawMatrix) int {
+	if a.Rows == 0 || a.Cols == 0 {
+		return 0
+	}
+	return (a.Rows-1)*a.Stride + a.Cols
+}
+
+// accumulate sets the receiver to alpha*p + beta*m. As for BLAS, the receiver
+// is not read when beta is zero.
+func (m *Dense) accumulate(alpha float64, p *Dense, beta float64) {
+	for i := 0; i < m.mat.Rows; i++ {
+		row := m.rowView(i)
+		for j, v := range p.rowView(i) {
+			if beta == 0 {
+				row[j] = alpha * v
+			} else {
+				row[j] = alpha*v + beta*row[j]
+			}
+		}
+	}
+}
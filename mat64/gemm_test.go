@@ -0,0 +1,154 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+)
+
+// gemmNaive returns alpha*op(a)*op(b) + beta*c computed element by element.
+func gemmNaive(alpha float64, a Matrix, aTrans bool, b Matrix, bTrans bool, beta float64, c Matrix) *Dense {
+	a, b = transposeIf(a, aTrans), transposeIf(b, bTrans)
+	r, k := a.Dims()
+	_, n := b.Dims()
+	d := NewDense(r, n, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < n; j++ {
+			var v float64
+			for l := 0; l < k; l++ {
+				v += a.At(i, l) * b.At(l, j)
+			}
+			d.Set(i, j, alpha*v+beta*c.At(i, j))
+		}
+	}
+	return d
+}
+
+func (s *S) TestGemm(c *check.C) {
+	a := NewDense(flatten([][]float64{
+		{1, 2, 3},
+		{-4, 5, 6},
+	}))
+	b := NewDense(flatten([][]float64{
+		{0.5, -1, 2},
+		{3, 1, -2},
+	}))
+	sq := NewDense(flatten([][]float64{
+		{2, -1},
+		{1, 3},
+	}))
+	for i, test := range []struct {
+		a, b           Matrix
+		aTrans, bTrans bool
+		alpha, beta    float64
+	}{
+		{a: a, b: b, aTrans: false, bTrans: true, alpha: 1, beta: 0},
+		{a: a, b: b, aTrans: true, bTrans: false, alpha: 2, beta: -1},
+		{a: sq, b: a, aTrans: true, bTrans: false, alpha: -0.5, beta: 3},
+		{a: a.T(), b: b, aTrans: true, bTrans: true, alpha: 1, beta: 1},
+		{a: Vec{1, 2}, b: Vec{3, -4}, aTrans: false, bTrans: true, alpha: 1, beta: 2},
+	} {
+		r, _ := transposeIf(test.a, test.aTrans).Dims()
+		_, n := transposeIf(test.b, test.bTrans).Dims()
+		m := NewDense(r, n, nil)
+		for j := range m.mat.Data {
+			m.mat.Data[j] = float64(j) - 1.5
+		}
+		want := gemmNaive(test.alpha, test.a, test.aTrans, test.b, test.bTrans, test.beta, m)
+
+		m.Gemm(test.alpha, test.a, test.aTrans, test.b, test.bTrans, test.beta)
+		c.Check(m.EqualsApprox(want, 1e-14), check.Equals, true, check.Commentf("Test %d", i))
+
+		var z Dense
+		z.Gemm(test.alpha, test.a, test.aTrans, test.b, test.bTrans, test.beta)
+		want = gemmNaive(test.alpha, test.a, test.aTrans, test.b, test.bTrans, 0, NewDense(r, n, nil))
+		c.Check(z.EqualsApprox(want, 1e-14), check.Equals, true, check.Commentf("Test %d", i))
+
+		var mt Dense
+		mt.MulTrans(test.a, test.aTrans, test.b, test.bTrans)
+		want = gemmNaive(1, test.a, test.aTrans, test.b, test.bTrans, 0, NewDense(r, n, nil))
+		c.Check(mt.EqualsApprox(want, 1e-14), check.Equals, true, check.Commentf("Test %d", i))
+	}
+
+	// The receiver may also be an operand.
+	m := NewDense(flatten([][]float64{
+		{1, 2},
+		{3, 4},
+	}))
+	want := gemmNaive(2, m, true, m, false, 1, m)
+	m.Gemm(2, m, true, m, false, 1)
+	c.Check(m.EqualsApprox(want, 1e-14), check.Equals, true)
+
+	// Views sharing the receiver's storage are read before it is written.
+	m = NewDense(flatten([][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 9},
+	}))
+	var top, left Dense
+	top.View(m, 0, 0, 2, 3)
+	left.View(m, 0, 0, 3, 2)
+	var sub Dense
+	sub.View(m, 1, 1, 2, 2)
+	want = gemmNaive(1, &top, false, &left, false, -1, &sub)
+	sub.Gemm(1, &top, false, &left, false, -1)
+	c.Check(sub.EqualsApprox(want, 1e-14), check.Equals, true)
+
+	c.Check(func() { a.Gemm(1, a, false, b, false, 0) }, check.PanicMatches, ".*dimension mismatch.*")
+}
+
+func (s *S) TestSymRankK(c *check.C) {
+	a := NewDense(flatten([][]float64{
+		{1, 2, 3},
+		{-4, 5, 6},
+	}))
+	for i, test := range []struct {
+		a           Matrix
+		trans       bool
+		alpha, beta float64
+	}{
+		{a: a, trans: false, alpha: 1, beta: 0},
+		{a: a, trans: true, alpha: 0.5, beta: 2},
+		{a: a.T(), trans: false, alpha: -1, beta: 1},
+		{a: Vec{1, -2, 3}, trans: false, alpha: 1, beta: -1},
+	} {
+		n, _ := transposeIf(test.a, test.trans).Dims()
+		sym := NewDense(n, n, nil)
+		for r := 0; r < n; r++ {
+			for c := 0; c <= r; c++ {
+				v := float64(r*n+c) - 2
+				sym.Set(r, c, v)
+				sym.Set(c, r, v)
+			}
+		}
+		want := gemmNaive(test.alpha, test.a, test.trans, test.a, !test.trans, test.beta, sym)
+
+		sym.SymRankK(test.alpha, test.a, test.trans, test.beta)
+		c.Check(sym.EqualsApprox(want, 1e-14), check.Equals, true, check.Commentf("Test %d", i))
+
+		var z Dense
+		z.SymRankK(test.alpha, test.a, test.trans, test.beta)
+		want = gemmNaive(test.alpha, test.a, test.trans, test.a, !test.trans, 0, NewDense(n, n, nil))
+		c.Check(z.EqualsApprox(want, 1e-14), check.Equals, true, check.Commentf("Test %d", i))
+	}
+
+	// An operand sharing the receiver's storage is read before it is written,
+	// and only the upper triangle of the receiver is read.
+	m := NewDense(flatten([][]float64{
+		{1, 2, 3},
+		{100, 4, 5},
+		{100, 100, 6},
+	}))
+	sym := NewDense(flatten([][]float64{
+		{1, 2, 3},
+		{2, 4, 5},
+		{3, 5, 6},
+	}))
+	var v Dense
+	v.View(m, 0, 0, 3, 2)
+	want := gemmNaive(2, &v, false, &v, true, 3, sym)
+	m.SymRankK(2, &v, false, 3)
+	c.Check(m.EqualsApprox(want, 1e-14), check.Equals, true)
+}
@@ -0,0 +1,348 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// maxJacobiSweeps is the maximum number of sweeps over all pairs of vectors
+// performed by JacobiSVD.
+const maxJacobiSweeps = 100
+
+// JacobiSVD performs singular value decomposition for an m-by-n matrix a using
+// one-sided Jacobi rotations preconditioned by a QR decomposition with column
+// pivoting. The factors
+// have the same shape as those returned by SVD: an m-by-min(m, n) matrix u, the
+// singular values sigma in decreasing order and an n-by-min(m, n) matrix v so
+// that a = u*s*v'. Matrices u and v are only created when wantu and wantv are
+// true respectively. The matrix a is not altered.
+//
+// Unlike SVD, JacobiSVD computes the singular values of a row or column graded
+// matrix a = d*b or a = b*d, where d is diagonal and b is well conditioned, to
+// high relative accuracy, including the smallest ones.
+//
+// Rotations are considered converged when the cosine of the angle between each
+// pair of vectors of the triangular factor is no greater than epsilon. Each step
+// of a sweep rotates disjoint pairs, which are distributed over workers goroutines. If
+// workers is less than 2 the rotations are performed sequentially.
+func JacobiSVD(a *Dense, epsilon float64, wantu, wantv bool, workers int) SVDFactors {
+	m, n := a.Dims()
+
+	trans := false
+	if m < n {
+		m, n = n, m
+		wantu, wantv = wantv, wantu
+		trans = true
+	}
+
+	// Order rows by decreasing max norm so that the Householder QR
+	// decomposition preserves the grading of a.
+	at := func(i, j int) float64 { return a.At(i, j) }
+	if trans {
+		at = func(i, j int) float64 { return a.At(j, i) }
+	}
+	rowNorm := make([]float64, m)
+	for i := range rowNorm {
+		for j := 0; j < n; j++ {
+			rowNorm[i] = math.Max(rowNorm[i], math.Abs(at(i, j)))
+		}
+	}
+	rowPerm := byDecreasing(rowNorm)
+
+	pa := NewDense(m, n, nil)
+	for i, pi := range rowPerm {
+		for j := 0; j < n; j++ {
+			pa.Set(i, j, at(pi, j))
+		}
+	}
+	qr, colPerm := pivotedQR(pa)
+
+	// Rotate the rows of r until they are mutually orthogonal, accumulating
+	// the rotations in the rows of j, so that r = j'*w where the rows of w
+	// hold the scaled right singular vectors of r. Working on the rows keeps
+	// the rotations accurate for the row graded factor of a graded a.
+	w := qr.R()
+	j := NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		j.Set(i, i, 1)
+	}
+	jacobiRotate(w, j, epsilon, workers)
+
+	sigma := make([]float64, n)
+	for k := range sigma {
+		var norm float64
+		for _, v := range w.rowView(k) {
+			norm = math.Hypot(norm, v)
+		}
+		sigma[k] = norm
+	}
+	order := byDecreasing(sigma)
+	sorted := make([]float64, n)
+	for k, i := range order {
+		sorted[k] = sigma[i]
+	}
+
+	var u, v *Dense
+	if wantu {
+		jt := NewDense(n, n, nil)
+		for k, i := range order {
+			jt.SetCol(k, j.rowView(i))
+		}
+		q := qr.Q()
+		ut := &Dense{}
+		ut.Mul(q, jt)
+		u = NewDense(m, n, nil)
+		for i, pi := range rowPerm {
+			u.SetRow(pi, ut.rowView(i))
+		}
+	}
+	if wantv {
+		// Normalise the rows of w to give the right singular vectors
+		// of r, completing the basis where sigma is zero.
+		vr := NewDense(n, n, nil)
+		for k, i := range order {
+			if sorted[k] == 0 {
+				break
+			}
+			for c, e := range w.rowView(i) {
+				vr.Set(c, k, e/sorted[k])
+			}
+		}
+		completeBasis(vr, sorted)
+
+		v = NewDense(n, n, nil)
+		for i, pi := range colPerm {
+			v.SetRow(pi, vr.rowView(i))
+		}
+	}
+
+	if trans {
+		u, v = v, u
+	}
+	return SVDFactors{
+		U:     u,
+		Sigma: sorted,
+		V:     v,
+
+		m: m, n: n,
+	}
+}
+
+// pivotedQR computes a QR decomposition of a with column pivoting, such that
+// the columns of a permuted by piv equal q.r, where the diagonal of r has
+// decreasing magnitude. The matrix a is overwritten by the decomposition as
+// for QR.
+func pivotedQR(a *Dense) (f QRFactor, piv []int) {
+	m, n := a.Dims()
+	if m < n {
+		panic(opError(ErrShape, "pivotedQR", a))
+	}
+
+	qr := a
+	rDiag := make([]float64, n)
+	piv = make([]int, n)
+	for i := range piv {
+		piv[i] = i
+	}
+
+	for k := 0; k < n; k++ {
+		// Find the remaining column of largest norm and move it to k.
+		p, pNorm := k, -1.
+		for j := k; j < n; j++ {
+			var norm float64
+			for i := k; i < m; i++ {
+				norm = math.Hypot(norm, qr.At(i, j))
+			}
+			if norm > pNorm {
+				p, pNorm = j, norm
+			}
+		}
+		if p != k {
+			for i := 0; i < m; i++ {
+				t := qr.At(i, p)
+				qr.Set(i, p, qr.At(i, k))
+				qr.Set(i, k, t)
+			}
+			piv[p], piv[k] = piv[k], piv[p]
+		}
+
+		norm := pNorm
+		if norm != 0 {
+			// Form k-th Householder vector.
+			if qr.At(k, k) < 0 {
+				norm = -norm
+			}
+			for i := k; i < m; i++ {
+				qr.Set(i, k, qr.At(i, k)/norm)
+			}
+			qr.Set(k, k, qr.At(k, k)+1)
+
+			// Apply transformation to remaining columns.
+			for j := k + 1; j < n; j++ {
+				var s float64
+				for i := k; i < m; i++ {
+					s += qr.At(i, k) * qr.At(i, j)
+				}
+				s /= -qr.At(k, k)
+				for i := k; i < m; i++ {
+					qr.Set(i, j, qr.At(i, j)+s*qr.At(i, k))
+				}
+			}
+		}
+		rDiag[k] = -norm
+	}
+
+	return QRFactor{qr, rDiag}, piv
+}
+
+// byDecreasing returns the permutation of indices that orders f by decreasing value.
+func byDecreasing(f []float64) []int {
+	p := make([]int, len(f))
+	for i := range p {
+		p[i] = i
+	}
+	sort.SliceStable(p, func(i, j int) bool { return f[p[i]] > f[p[j]] })
+	return p
+}
+
+// jacobiRotate applies one-sided Jacobi rotations to pairs of rows of w until
+// all rows are orthogonal to within epsilon, applying the same rotations to the
+// rows of v. Pairs are visited in round-robin order so that each step holds
+// disjoint pairs that can be rotated concurrently.
+func jacobiRotate(w, v *Dense, epsilon float64, workers int) {
+	n, _ := w.Dims()
+	if n < 2 {
+		return
+	}
+
+	// Round-robin tournament on an even number of players; a player
+	// index of n is a bye when n is odd.
+	players := n + n%2
+	steps := make([][][2]int, players-1)
+	for r := range steps {
+		pairs := [][2]int{{r, players - 1}}
+		for k := 1; k < players/2; k++ {
+			pairs = append(pairs, [2]int{(r + k) % (players - 1), (r - k + players - 1) % (players - 1)})
+		}
+		for _, p := range pairs {
+			if p[0] < n && p[1] < n {
+				steps[r] = append(steps[r], p)
+			}
+		}
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+	rotated := make([]bool, workers)
+	for sweep := 0; sweep < maxJacobiSweeps; sweep++ {
+		var any bool
+		for _, pairs := range steps {
+			nw := min(workers, len(pairs))
+			if nw < 2 {
+				for _, p := range pairs {
+					any = rotatePair(w, v, p[0], p[1], epsilon) || any
+				}
+				continue
+			}
+			var wg sync.WaitGroup
+			for k := 0; k < nw; k++ {
+				wg.Add(1)
+				go func(k int) {
+					defer wg.Done()
+					for i := k; i < len(pairs); i += nw {
+						rotated[k] = rotatePair(w, v, pairs[i][0], pairs[i][1], epsilon) || rotated[k]
+					}
+				}(k)
+			}
+			wg.Wait()
+			for k := range rotated[:nw] {
+				any = any || rotated[k]
+				rotated[k] = false
+			}
+		}
+		if !any {
+			return
+		}
+	}
+}
+
+// rotatePair orthogonalises rows p and q of w by a plane rotation that is also
+// applied to rows p and q of v. It returns whether a rotation was applied.
+func rotatePair(w, v *Dense, p, q int, epsilon float64) bool {
+	wp, wq := w.rowView(p), w.rowView(q)
+	var alpha, beta, gamma float64
+	for i, e := range wp {
+		alpha += e * e
+		beta += wq[i] * wq[i]
+		gamma += e * wq[i]
+	}
+	if gamma == 0 || math.Abs(gamma) <= epsilon*math.Sqrt(alpha)*math.Sqrt(beta) {
+		return false
+	}
+
+	zeta := (beta - alpha) / (2 * gamma)
+	t := 1 / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+	if zeta < 0 {
+		t = -t
+	}
+	c := 1 / math.Sqrt(1+t*t)
+	s := c * t
+
+	for _, r := range [][2][]float64{{wp, wq}, {v.rowView(p), v.rowView(q)}} {
+		x, y := r[0], r[1]
+		for i, e := range x {
+			x[i] = c*e - s*y[i]
+			y[i] = s*e + c*y[i]
+		}
+	}
+	return true
+}
+
+// completeBasis replaces the columns of the square matrix u corresponding to
+// zero values of sigma with unit vectors orthogonal to all other columns.
+func completeBasis(u *Dense, sigma []float64) {
+	n, _ := u.Dims()
+	col := make([]float64, n)
+	e := 0
+	for k, s := range sigma {
+		if s != 0 {
+			continue
+		}
+		for ; e < n; e++ {
+			for i := range col {
+				col[i] = 0
+			}
+			col[e] = 1
+
+			// Orthogonalise twice against the filled columns.
+			for pass := 0; pass < 2; pass++ {
+				for j := 0; j < k; j++ {
+					var d float64
+					for i := range col {
+						d += u.At(i, j) * col[i]
+					}
+					for i := range col {
+						col[i] -= d * u.At(i, j)
+					}
+				}
+			}
+			var norm float64
+			for _, v := range col {
+				norm = math.Hypot(norm, v)
+			}
+			if norm > 0.5 {
+				for i, v := range col {
+					u.Set(i, k, v/norm)
+				}
+				e++
+				break
+			}
+		}
+	}
+}
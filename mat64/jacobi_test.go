@@ -0,0 +1,142 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+)
+
+// hadamard returns the n-by-n Sylvester Hadamard matrix scaled to be orthogonal.
+// The elements are exactly representable when n is an even power of two.
+func hadamard(n int) *Dense {
+	h := NewDense(n, n, nil)
+	s := 1 / math.Sqrt(float64(n))
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			v := s
+			for b := i & j; b != 0; b &= b - 1 {
+				v = -v
+			}
+			h.Set(i, j, v)
+		}
+	}
+	return h
+}
+
+func maxRelErr(got, want []float64) float64 {
+	var e float64
+	for i, v := range want {
+		e = math.Max(e, math.Abs(got[i]-v)/v)
+	}
+	return e
+}
+
+func (s *S) TestJacobiSVD(c *check.C) {
+	// q is an exactly orthogonal matrix without the symmetry of a
+	// Hadamard matrix.
+	p := NewDense(4, 4, []float64{
+		0, 1, 0, 0,
+		0, 0, 0, -1,
+		1, 0, 0, 0,
+		0, 0, 1, 0,
+	})
+	var q Dense
+	q.Mul(hadamard(4), p)
+	q.Mul(&q, hadamard(4))
+	var h16 Dense
+	h16.View(hadamard(16), 0, 0, 16, 4)
+	q16 := &Dense{}
+	q16.Mul(&h16, &q)
+
+	sigma := []float64{1, 1e-5, 1e-10, 1e-15}
+	graded := func(perm ...int) *Dense {
+		d := NewDense(4, 4, nil)
+		for i, p := range perm {
+			d.Set(i, i, sigma[p])
+		}
+		return d
+	}
+
+	for i, test := range []struct {
+		a       func() *Dense
+		workers int
+	}{
+		{ // Column graded.
+			a: func() *Dense {
+				var a Dense
+				a.Mul(&q, graded(1, 3, 0, 2))
+				return &a
+			},
+			workers: 1,
+		},
+		{ // Row graded.
+			a: func() *Dense {
+				var a Dense
+				a.Mul(graded(3, 0, 2, 1), &q)
+				return &a
+			},
+			workers: 2,
+		},
+		{ // Tall column graded.
+			a: func() *Dense {
+				var a Dense
+				a.Mul(q16, graded(2, 3, 0, 1))
+				return &a
+			},
+			workers: 4,
+		},
+		{ // Wide row graded.
+			a: func() *Dense {
+				var a, qt Dense
+				qt.TCopy(q16)
+				a.Mul(graded(3, 2, 1, 0), &qt)
+				return &a
+			},
+			workers: 3,
+		},
+	} {
+		a := test.a()
+		orig := DenseCopyOf(a)
+		svd := JacobiSVD(a, math.Pow(2, -52.0), true, true, test.workers)
+		c.Check(a.Equals(orig), check.Equals, true, check.Commentf("Test %d: input altered", i))
+
+		gk := SVD(DenseCopyOf(a), math.Pow(2, -52.0), math.Pow(2, -966.0), false, false)
+		jErr, gkErr := maxRelErr(svd.Sigma, sigma), maxRelErr(gk.Sigma, sigma)
+		c.Check(jErr < 1e-14, check.Equals, true, check.Commentf("Test %d: relative error %v", i, jErr))
+		c.Check(gkErr > 1e3*jErr, check.Equals, true, check.Commentf("Test %d: %v not better than %v", i, jErr, gkErr))
+
+		m, n := a.Dims()
+		k := min(m, n)
+		ur, uc := svd.U.Dims()
+		vr, vc := svd.V.Dims()
+		c.Check([]int{ur, uc, vr, vc}, check.DeepEquals, []int{m, k, n, k}, check.Commentf("Test %d", i))
+		c.Check(isOrthogonal(svd.U), check.Equals, true, check.Commentf("Test %d: U not orthogonal", i))
+		c.Check(isOrthogonal(svd.V), check.Equals, true, check.Commentf("Test %d: V not orthogonal", i))
+
+		var us, vt Dense
+		vt.TCopy(svd.V)
+		us.Mul(svd.U, svd.S())
+		us.Mul(&us, &vt)
+		c.Check(us.EqualsApprox(orig, 1e-14), check.Equals, true, check.Commentf("Test %d", i))
+	}
+
+	// Rank deficient input has orthogonal factors.
+	a := NewDense(4, 3, []float64{
+		1, 2, 3,
+		2, 4, 6,
+		1, 0, 1,
+		0, 0, 0,
+	})
+	svd := JacobiSVD(a, math.Pow(2, -52.0), true, true, 1)
+	c.Check(svd.Sigma[2] < 1e-14, check.Equals, true)
+	c.Check(isOrthogonal(svd.U), check.Equals, true)
+	c.Check(isOrthogonal(svd.V), check.Equals, true)
+	var us, vt Dense
+	vt.TCopy(svd.V)
+	us.Mul(svd.U, svd.S())
+	us.Mul(&us, &vt)
+	c.Check(us.EqualsApprox(a, 1e-13), check.Equals, true)
+}
@@ -0,0 +1,390 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// A MatVec computes the product y = A*x of an implicitly defined n-by-n matrix
+// A and the vector x, placing the result in y. It must not retain or modify x.
+type MatVec func(y, x []float64)
+
+// Which specifies the eigenvalues computed by the Lanczos and Arnoldi eigensolvers.
+type Which int
+
+const (
+	// LargestMagnitude selects the eigenvalues of largest modulus.
+	LargestMagnitude Which = iota
+	// SmallestMagnitude selects the eigenvalues of smallest modulus.
+	SmallestMagnitude
+	// LargestAlgebraic selects the eigenvalues with the largest real part.
+	LargestAlgebraic
+	// SmallestAlgebraic selects the eigenvalues with the smallest real part.
+	SmallestAlgebraic
+)
+
+// Lanczos computes k eigenvalues and eigenvectors of the n-by-n symmetric matrix
+// represented by a using the implicitly restarted Lanczos method. The eigenvalues
+// are selected according to which and are held in the returned factors in that
+// order, with the corresponding orthonormal eigenvectors in the columns of the
+// n-by-k matrix V.
+//
+// At most ncv Lanczos vectors are held, with k < ncv <= n; if ncv is not positive
+// min(n, max(2k+1, 20)) vectors are used. Larger values of ncv need more storage
+// but fewer restarts, and SmallestMagnitude in particular may need many. An
+// eigenpair (lambda, x) is accepted when ||a*x - lambda*x|| <= tol*max(eps^(2/3), |lambda|),
+// with eps the machine precision, as in ARPACK. If tol is not positive machine
+// precision is used. The starting vector is drawn from rnd, or from the default
+// source of math/rand if rnd is nil.
+//
+// If the eigenpairs have not converged after maxIter restarts, the current
+// approximations are returned with ErrNoConvergence. Lanczos will panic with
+// ErrShape if k or ncv is out of range.
+func Lanczos(a MatVec, n, k, ncv int, which Which, tol float64, maxIter int, rnd *rand.Rand) (EigenFactors, error) {
+	return restartedArnoldi(a, n, k, ncv, which, tol, maxIter, rnd, true)
+}
+
+// Arnoldi computes k eigenvalues and eigenvectors of the n-by-n general matrix
+// represented by a using the implicitly restarted Arnoldi method. The eigenvalues
+// are selected according to which and are held in the returned factors in that
+// order. As for Eigen, complex eigenvalues are held as conjugate pairs with their
+// eigenvectors in the real and imaginary parts of adjacent columns of the n-by-k
+// matrix V, so that a*V = V*D; if the k-th eigenvalue is complex its conjugate is
+// included and k+1 eigenpairs are returned.
+//
+// The parameters ncv, tol, maxIter and rnd are interpreted as for Lanczos, except
+// that ncv must be at least k+2. Arnoldi will panic with ErrShape if k or ncv is
+// out of range.
+func Arnoldi(a MatVec, n, k, ncv int, which Which, tol float64, maxIter int, rnd *rand.Rand) (EigenFactors, error) {
+	if ncv > 0 && ncv < k+2 {
+		panic(opError(ErrShape, "Arnoldi"))
+	}
+	return restartedArnoldi(a, n, k, ncv, which, tol, maxIter, rnd, false)
+}
+
+func restartedArnoldi(a MatVec, n, k, ncv int, which Which, tol float64, maxIter int, rnd *rand.Rand, sym bool) (EigenFactors, error) {
+	op := "Arnoldi"
+	if sym {
+		op = "Lanczos"
+	}
+	if k < 1 || k >= n {
+		panic(opError(ErrShape, op))
+	}
+	m := ncv
+	if m <= 0 {
+		m = min(n, max(2*k+1, 20))
+	}
+	if m <= k || m > n {
+		panic(opError(ErrShape, op))
+	}
+	if tol <= 0 {
+		tol = epsilon
+	}
+	norm := rand.NormFloat64
+	if rnd != nil {
+		norm = rnd.NormFloat64
+	}
+
+	// The rows of v hold the Krylov basis and h the projection of a onto
+	// it, so that a*v' = v'*h + f*e_m'.
+	v := NewDense(m, n, nil)
+	h := NewDense(m, m, nil)
+	f := make([]float64, n)
+	for i := range f {
+		f[i] = norm()
+	}
+	eps23 := math.Pow(epsilon, 2./3)
+
+	for j, iter := 0, 0; ; iter++ {
+		beta := extendArnoldi(a, v, h, f, j, sym, norm)
+
+		// Compute the Ritz values and vectors of h.
+		hc := DenseCopyOf(h)
+		if sym {
+			for i := 1; i < m; i++ {
+				hc.Set(i-1, i, hc.At(i, i-1))
+			}
+		}
+		ef := Eigen(hc, epsilon)
+		d, e, y := ef.d, ef.e, ef.V
+
+		wanted := selectRitz(d, e, k, which)
+		kk := len(wanted)
+		converged := true
+		for _, i := range wanted {
+			if ritzResidual(y, e, i, beta) > tol*math.Max(eps23, math.Hypot(d[i], e[i])) {
+				converged = false
+				break
+			}
+		}
+		if converged || iter >= maxIter || kk >= m {
+			var err error
+			if !converged {
+				err = ErrNoConvergence
+			}
+			return ritzPairs(v, y, d, e, wanted), err
+		}
+
+		// Apply the unwanted Ritz values as exact shifts.
+		q := NewDense(m, m, nil)
+		for i := 0; i < m; i++ {
+			q.Set(i, i, 1)
+		}
+		isWanted := make([]bool, m)
+		for _, i := range wanted {
+			isWanted[i] = true
+		}
+		for i := 0; i < m; i++ {
+			switch {
+			case isWanted[i] || e[i] < 0:
+				// Wanted, or the conjugate of a complex shift.
+			default:
+				shiftArnoldi(h, q, sym, d[i], e[i])
+			}
+		}
+
+		// Truncate the factorization to kk vectors.
+		qt := &Dense{}
+		qt.TCopy(q)
+		vq := &Dense{}
+		vq.Mul(qt, v)
+		bk, sk := h.At(kk, kk-1), q.At(m-1, kk-1)
+		for i, r := range vq.rowView(kk) {
+			f[i] = r*bk + f[i]*sk
+		}
+		v.Copy(vq)
+		for i := 0; i < m; i++ {
+			for c := 0; c < m; c++ {
+				if i >= kk || c >= kk {
+					h.Set(i, c, 0)
+				}
+			}
+		}
+		j = kk
+	}
+}
+
+// extendArnoldi extends the Arnoldi factorization held in the first j rows of v
+// and the leading j-by-j block of h, with residual f, to the full number of rows
+// of v. Each new vector is reorthogonalized against the basis. If sym is true
+// only the tridiagonal part of h is formed. The norm of the final residual, held
+// in f, is returned.
+func extendArnoldi(a MatVec, v, h *Dense, f []float64, j int, sym bool, norm func() float64) float64 {
+	m, _ := v.Dims()
+	c := make([]float64, m)
+	var beta float64
+	for ; j < m; j++ {
+		beta = vecNorm(f)
+		if j > 0 && beta <= epsilon*h.Norm(0) {
+			// An invariant subspace has been found, so continue with a
+			// random vector orthogonal to the basis.
+			for i := range f {
+				f[i] = norm()
+			}
+			orthogonalize(v, j, f, c)
+			beta = vecNorm(f)
+			h.Set(j, j-1, 0)
+		} else if j > 0 {
+			h.Set(j, j-1, beta)
+		}
+		vj := v.rowView(j)
+		for i, e := range f {
+			vj[i] = e / beta
+		}
+
+		a(f, vj)
+		orthogonalize(v, j+1, f, c)
+		if sym {
+			h.Set(j, j, c[j])
+			if j > 0 {
+				h.Set(j-1, j, h.At(j, j-1))
+			}
+		} else {
+			for i := 0; i <= j; i++ {
+				h.Set(i, j, c[i])
+			}
+		}
+	}
+	return vecNorm(f)
+}
+
+// orthogonalize removes from f its components in the first j rows of v, applying
+// classical Gram-Schmidt twice, and stores the total components in c.
+func orthogonalize(v *Dense, j int, f, c []float64) {
+	for i := range c[:j] {
+		c[i] = 0
+	}
+	for pass := 0; pass < 2; pass++ {
+		for i := 0; i < j; i++ {
+			var s float64
+			for l, e := range v.rowView(i) {
+				s += e * f[l]
+			}
+			for l, e := range v.rowView(i) {
+				f[l] -= s * e
+			}
+			c[i] += s
+		}
+	}
+}
+
+func vecNorm(f []float64) float64 {
+	var s float64
+	for _, v := range f {
+		s = math.Hypot(s, v)
+	}
+	return s
+}
+
+// shiftArnoldi applies a shifted QR step to the Hessenberg matrix h, replacing it
+// with q1'*h*q1 where p(h) = q1*r, and accumulates q1 into q. If im is zero, p(h)
+// is h - re*I, otherwise it is the real product (h - z*I)*(h - conj(z)*I) for the
+// complex shift z = re + im*i.
+func shiftArnoldi(h, q *Dense, sym bool, re, im float64) {
+	m, _ := h.Dims()
+	p := &Dense{}
+	if im == 0 {
+		p.Clone(h)
+		for i := 0; i < m; i++ {
+			p.Set(i, i, p.At(i, i)-re)
+		}
+	} else {
+		t := &Dense{}
+		t.Scale(-2*re, h)
+		p.Mul(h, h)
+		p.Add(p, t)
+		for i := 0; i < m; i++ {
+			p.Set(i, i, p.At(i, i)+re*re+im*im)
+		}
+	}
+	q1 := QR(p).Q()
+
+	qt := &Dense{}
+	qt.TCopy(q1)
+	h.Mul(qt, h)
+	h.Mul(h, q1)
+	q.Mul(q, q1)
+
+	// Restore the structure lost to rounding.
+	for i := 0; i < m; i++ {
+		for c := 0; c < i-1; c++ {
+			h.Set(i, c, 0)
+		}
+		if sym {
+			for c := i + 2; c < m; c++ {
+				h.Set(i, c, 0)
+			}
+			if i > 0 {
+				s := (h.At(i, i-1) + h.At(i-1, i)) / 2
+				h.Set(i, i-1, s)
+				h.Set(i-1, i, s)
+			}
+		}
+	}
+}
+
+// selectRitz returns the indices of the k Ritz values held in d and e that are
+// wanted according to which, in order of preference. If the last selected value
+// is one of a complex conjugate pair its conjugate is also selected.
+func selectRitz(d, e []float64, k int, which Which) []int {
+	idx := make([]int, len(d))
+	for i := range idx {
+		idx[i] = i
+	}
+	key := func(i int) float64 {
+		switch which {
+		case LargestMagnitude:
+			return -math.Hypot(d[i], e[i])
+		case SmallestMagnitude:
+			return math.Hypot(d[i], e[i])
+		case LargestAlgebraic:
+			return -d[i]
+		case SmallestAlgebraic:
+			return d[i]
+		}
+		panic(ErrWhich)
+	}
+	// Conjugate pairs have equal keys and are adjacent in d and e, so a
+	// stable sort keeps them together.
+	sort.SliceStable(idx, func(i, j int) bool { return key(idx[i]) < key(idx[j]) })
+	if e[idx[k-1]] > 0 && k < len(idx) {
+		k++
+	}
+	return idx[:k]
+}
+
+// ritzResidual returns the residual norm of the Ritz pair held at index i of the
+// eigenvectors y of the projected matrix, given the norm beta of the residual of
+// the Arnoldi factorization.
+func ritzResidual(y *Dense, e []float64, i int, beta float64) float64 {
+	m, _ := y.Dims()
+	re, im := i, -1
+	switch {
+	case e[i] > 0:
+		im = i + 1
+	case e[i] < 0:
+		re, im = i-1, i
+	}
+	var norm, last float64
+	for r := 0; r < m; r++ {
+		norm = math.Hypot(norm, y.At(r, re))
+		if im >= 0 {
+			norm = math.Hypot(norm, y.At(r, im))
+		}
+	}
+	last = math.Abs(y.At(m-1, re))
+	if im >= 0 {
+		last = math.Hypot(last, y.At(m-1, im))
+	}
+	return beta * last / norm
+}
+
+// ritzPairs returns the wanted Ritz pairs as eigen factors, with the Ritz vectors
+// formed from the basis in the rows of v and normalized.
+func ritzPairs(v, y *Dense, d, e []float64, wanted []int) EigenFactors {
+	m, n := v.Dims()
+	k := len(wanted)
+	yk := NewDense(m, k, nil)
+	dk := make([]float64, k)
+	ek := make([]float64, k)
+	for c, i := range wanted {
+		for r := 0; r < m; r++ {
+			yk.Set(r, c, y.At(r, i))
+		}
+		dk[c], ek[c] = d[i], e[i]
+	}
+	vt := &Dense{}
+	vt.TCopy(v)
+	x := NewDense(n, k, nil)
+	x.Mul(vt, yk)
+
+	col := make([]float64, n)
+	for c := 0; c < k; c++ {
+		norm := vecNorm(x.Col(col, c))
+		if ek[c] != 0 {
+			// Normalize the conjugate pair as a complex vector.
+			pair := c + 1
+			if ek[c] < 0 {
+				pair = c - 1
+			}
+			norm = math.Hypot(norm, vecNorm(x.Col(col, pair)))
+			if ek[c] < 0 {
+				// The pair was normalized with its first member.
+				continue
+			}
+			for r := 0; r < n; r++ {
+				x.Set(r, pair, x.At(r, pair)/norm)
+			}
+		}
+		for r := 0; r < n; r++ {
+			x.Set(r, c, x.At(r, c)/norm)
+		}
+	}
+
+	return EigenFactors{x, dk, ek}
+}
@@ -0,0 +1,151 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+	"math/rand"
+)
+
+// laplacian returns a MatVec for the n-by-n second difference matrix with
+// eigenvalues 2-2*cos(k*pi/(n+1)) for k = 1, ..., n.
+func laplacian(n int) MatVec {
+	return func(y, x []float64) {
+		for i := range y {
+			y[i] = 2 * x[i]
+			if i > 0 {
+				y[i] -= x[i-1]
+			}
+			if i < n-1 {
+				y[i] -= x[i+1]
+			}
+		}
+	}
+}
+
+func denseMatVec(a *Dense) MatVec {
+	return func(y, x []float64) {
+		for i := range y {
+			y[i] = 0
+			for j, v := range a.RowView(i) {
+				y[i] += v * x[j]
+			}
+		}
+	}
+}
+
+// checkEigenPairs checks that a*V = V*D for the n-by-n matrix represented by a.
+func checkEigenPairs(c *check.C, a MatVec, ef EigenFactors, tol float64, comment check.CommentInterface) {
+	n, k := ef.V.Dims()
+	av := NewDense(n, k, nil)
+	col := make([]float64, n)
+	y := make([]float64, n)
+	for j := 0; j < k; j++ {
+		a(y, ef.V.Col(col, j))
+		av.SetCol(j, y)
+	}
+	vd := &Dense{}
+	vd.Mul(ef.V, ef.D())
+	c.Check(av.EqualsApprox(vd, tol), check.Equals, true, comment)
+}
+
+func (s *S) TestLanczos(c *check.C) {
+	const n = 100
+	lambda := func(k int) float64 { return 2 - 2*math.Cos(float64(k)*math.Pi/(n+1)) }
+
+	for i, test := range []struct {
+		k, ncv int
+		which  Which
+		want   []float64
+	}{
+		{
+			k: 4, ncv: 20,
+			which: SmallestAlgebraic,
+			want:  []float64{lambda(1), lambda(2), lambda(3), lambda(4)},
+		},
+		{
+			k: 3, ncv: 0,
+			which: LargestAlgebraic,
+			want:  []float64{lambda(n), lambda(n - 1), lambda(n - 2)},
+		},
+		{
+			k: 2, ncv: 30,
+			which: LargestMagnitude,
+			want:  []float64{lambda(n), lambda(n - 1)},
+		},
+		{
+			k: 2, ncv: 40,
+			which: SmallestMagnitude,
+			want:  []float64{lambda(1), lambda(2)},
+		},
+	} {
+		ef, err := Lanczos(laplacian(n), n, test.k, test.ncv, test.which, 1e-10, 1000, rand.New(rand.NewSource(1)))
+		c.Check(err, check.IsNil, check.Commentf("Test %d", i))
+		for j, v := range test.want {
+			c.Check(math.Abs(ef.d[j]-v) < 1e-9*v, check.Equals, true, check.Commentf("Test %d: got %v want %v", i, ef.d, test.want))
+		}
+		c.Check(isOrthogonal(ef.V), check.Equals, true, check.Commentf("Test %d: V not orthogonal", i))
+		checkEigenPairs(c, laplacian(n), ef, 1e-8, check.Commentf("Test %d", i))
+	}
+
+	_, err := Lanczos(laplacian(n), n, 2, 4, SmallestAlgebraic, 1e-14, 1, rand.New(rand.NewSource(1)))
+	c.Check(err, check.Equals, ErrNoConvergence)
+}
+
+func (s *S) TestArnoldi(c *check.C) {
+	// a is an orthogonal similarity transform of a block diagonal matrix with
+	// eigenvalues 10±5i, 8 and 1, ..., 12/16.
+	const n = 16
+	b := NewDense(n, n, nil)
+	b.Set(0, 0, 10)
+	b.Set(0, 1, 5)
+	b.Set(1, 0, -5)
+	b.Set(1, 1, 10)
+	b.Set(2, 2, 8)
+	for i := 3; i < n; i++ {
+		b.Set(i, i, float64(i)/16)
+		b.Set(i-1, i, 0.5)
+	}
+	h := hadamard(n)
+	a := &Dense{}
+	a.Mul(h, b)
+	a.Mul(a, h)
+
+	for i, test := range []struct {
+		k      int
+		which  Which
+		wantRe []float64
+		wantIm []float64
+	}{
+		{
+			k:      1,
+			which:  LargestMagnitude,
+			wantRe: []float64{10, 10},
+			wantIm: []float64{5, -5},
+		},
+		{
+			k:      3,
+			which:  LargestAlgebraic,
+			wantRe: []float64{10, 10, 8},
+			wantIm: []float64{5, -5, 0},
+		},
+		{
+			k:      2,
+			which:  SmallestAlgebraic,
+			wantRe: []float64{3. / 16, 4. / 16},
+			wantIm: []float64{0, 0},
+		},
+	} {
+		ef, err := Arnoldi(denseMatVec(a), n, test.k, 10, test.which, 1e-12, 500, rand.New(rand.NewSource(1)))
+		c.Check(err, check.IsNil, check.Commentf("Test %d", i))
+		c.Assert(len(ef.d), check.Equals, len(test.wantRe), check.Commentf("Test %d: got %v+%vi", i, ef.d, ef.e))
+		for j := range test.wantRe {
+			c.Check(math.Abs(ef.d[j]-test.wantRe[j]) < 1e-9, check.Equals, true, check.Commentf("Test %d: got %v", i, ef.d))
+			c.Check(math.Abs(ef.e[j]-test.wantIm[j]) < 1e-9, check.Equals, true, check.Commentf("Test %d: got %v", i, ef.e))
+		}
+		checkEigenPairs(c, denseMatVec(a), ef, 1e-9, check.Commentf("Test %d", i))
+	}
+}
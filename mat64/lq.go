@@ -27,7 +27,7 @@ func LQ(a *Dense) LQFactor {
 	// Initialize.
 	m, n := a.Dims()
 	if m > n {
-		panic(ErrShape)
+		panic(opError(ErrShape, "LQ", a))
 	}
 
 	lq := *a
@@ -102,7 +102,7 @@ func (f LQFactor) applyQTo(x *Dense, trans bool) {
 	nh, nc := f.LQ.Dims()
 	m, n := x.Dims()
 	if m != nc {
-		panic(ErrShape)
+		panic(opError(ErrShape, "LQFactor.applyQTo", f.LQ, x))
 	}
 	proj := make([]float64, n)
 
@@ -156,10 +156,10 @@ func (f LQFactor) Solve(b *Dense) (x *Dense) {
 	m, n := lq.Dims()
 	bm, bn := b.Dims()
 	if bm != m {
-		panic(ErrShape)
+		panic(opError(ErrShape, "LQFactor.Solve", lq, b))
 	}
 	if !f.IsFullRank() {
-		panic("mat64: matrix is rank deficient")
+		panic(opError(ErrRankDeficient, "LQFactor.Solve", lq, b))
 	}
 
 	x = NewDense(n, bn, nil)
@@ -184,3 +184,43 @@ func (f LQFactor) Solve(b *Dense) (x *Dense) {
 
 	return x
 }
+
+// SolveTrans computes the least squares solution of a'.x = b where b has as many
+// rows as a has columns, using the factors of a rather than factorizing its
+// transpose. A matrix x is returned that minimizes the two norm of Q'*L'*X-B.
+// SolveTrans will panic if a is not full rank. The matrix b is not altered.
+func (f LQFactor) SolveTrans(b *Dense) (x *Dense) {
+	lq := f.LQ
+	lDiag := f.lDiag
+	m, n := lq.Dims()
+	bm, bn := b.Dims()
+	if bm != n {
+		panic(opError(ErrShape, "LQFactor.SolveTrans", lq, b))
+	}
+	if !f.IsFullRank() {
+		panic(opError(ErrRankDeficient, "LQFactor.SolveTrans", lq, b))
+	}
+
+	x = NewDense(n, bn, nil)
+	x.Copy(b)
+	f.applyQTo(x, false)
+
+	tau := make([]float64, m)
+	for i := range tau {
+		tau[i] = lq.At(i, i)
+		lq.Set(i, i, lDiag[i])
+	}
+	blasEngine.Dtrsm(
+		blas.RowMajor, blas.Left, blas.Lower, blas.Trans, blas.NonUnit,
+		m, bn,
+		1, lq.mat.Data, lq.mat.Stride,
+		x.mat.Data, x.mat.Stride,
+	)
+
+	for i := range tau {
+		lq.Set(i, i, tau[i])
+	}
+	x.View(x, 0, 0, m, bn)
+
+	return x
+}
@@ -0,0 +1,202 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+)
+
+// LSQResult holds the solution of a constrained or generalized linear least
+// squares problem.
+type LSQResult struct {
+	// X is the solution, with one column for each column of the right hand
+	// side.
+	X *Dense
+
+	// ResidualNorm holds the 2-norm of the residual of the minimized
+	// objective for each column of X.
+	ResidualNorm []float64
+
+	// Cov is the covariance matrix of the estimated parameters for
+	// observations with errors of unit variance, or with the given covariance
+	// for GLS.
+	Cov *Dense
+}
+
+// LSE returns the solution of the equality constrained least squares problem
+//  min ||a*x - b|| subject to c*x = d
+// for an m-by-n matrix a and a p-by-n matrix c with 0 < p <= n. The matrices b
+// and d must have m and p rows and the same number of columns, each of which
+// defines a problem.
+//
+// The problem is solved by the null space method. With an orthogonal matrix
+// q = [q1 q2] whose leading p columns span the rows of c, x = q1*y1 + q2*y2,
+// where y1 satisfies the constraints c*q1*y1 = d and y2 is the unconstrained
+// least squares solution of a*q2*y2 = b - a*q1*y1. The covariance of the
+// solution is q2*inv(q2'*a'*a*q2)*q2', which should be scaled by the variance
+// of the observation errors, estimated by ResidualNorm[j]^2/(m-n+p).
+//
+// LSE returns ErrSingular if c does not have full row rank or [a; c] does not
+// have full column rank, in which case the solution is not unique.
+func LSE(a, b, c, d Matrix) (LSQResult, error) {
+	m, n := a.Dims()
+	p, cn := c.Dims()
+	if cn != n || p > n {
+		panic(opError(ErrShape, "LSE", a, b, c, d))
+	}
+	bm, k := b.Dims()
+	dm, dk := d.Dims()
+	if bm != m || dm != p || dk != k {
+		panic(opError(ErrShape, "LSE", a, b, c, d))
+	}
+	if m+p < n {
+		return LSQResult{}, ErrSingular
+	}
+
+	var ct Dense
+	ct.TCopy(c)
+	q := householderBasis(&ct)
+
+	// Satisfy the constraints within the range of q1.
+	q1 := &Dense{}
+	q1.Submatrix(q, 0, 0, n, p)
+	cq1 := &Dense{}
+	cq1.Mul(c, q1)
+	lu := LU(cq1)
+	if lu.RCond() < epsilon {
+		return LSQResult{}, ErrSingular
+	}
+	x := &Dense{}
+	x.Mul(q1, lu.Solve(DenseCopyOf(d)))
+
+	cov := NewDense(n, n, nil)
+	if p < n {
+		// Minimize the residual within the null space of c.
+		q2 := &Dense{}
+		q2.Submatrix(q, 0, p, n, n-p)
+		aq2 := &Dense{}
+		aq2.Mul(a, q2)
+		f := QR(aq2)
+		if !f.IsFullRank() || f.RCond() < epsilon {
+			return LSQResult{}, ErrSingular
+		}
+		rhs := &Dense{}
+		rhs.Mul(a, x)
+		rhs.Sub(b, rhs)
+		dx := &Dense{}
+		dx.Mul(q2, f.Solve(rhs))
+		x.Add(x, dx)
+		cov = qrCov(f, q2)
+	}
+
+	return LSQResult{
+		X:            x,
+		ResidualNorm: residualNorms(a, x, b),
+		Cov:          cov,
+	}, nil
+}
+
+// GLS returns the solution of the generalized least squares problem
+//  min ||inv(l)*(a*x - b)||
+// for an m-by-n matrix a with m >= n and observation errors with the m-by-m
+// covariance w = l*l' given by its Cholesky factorization. Each column of b
+// defines a problem. The problem is whitened by triangular solves with l and
+// solved by QR factorization. ResidualNorm holds the norms of the whitened
+// residuals, and Cov is the covariance of the solution, inv(a'*inv(w)*a).
+//
+// GLS returns ErrNotPosDef if w is not symmetric positive definite and
+// ErrSingular if a does not have full column rank.
+func GLS(a, b Matrix, w CholeskyFactor) (LSQResult, error) {
+	m, n := a.Dims()
+	bm, _ := b.Dims()
+	wm, _ := w.L.Dims()
+	if bm != m || wm != m {
+		panic(opError(ErrShape, "GLS", a, b, w.L))
+	}
+	if !w.SPD {
+		return LSQResult{}, ErrNotPosDef
+	}
+	if m < n {
+		return LSQResult{}, ErrSingular
+	}
+
+	aw, bw := DenseCopyOf(a), DenseCopyOf(b)
+	solveLower(w.L, aw)
+	solveLower(w.L, bw)
+
+	f := QR(DenseCopyOf(aw))
+	if !f.IsFullRank() || f.RCond() < epsilon {
+		return LSQResult{}, ErrSingular
+	}
+	x := DenseCopyOf(f.Solve(DenseCopyOf(bw)))
+
+	return LSQResult{
+		X:            x,
+		ResidualNorm: residualNorms(aw, x, bw),
+		Cov:          qrCov(f, nil),
+	}, nil
+}
+
+// solveLower overwrites b with the solution of l*x = b for lower triangular l.
+func solveLower(l, b *Dense) {
+	n, _ := l.Dims()
+	_, c := b.Dims()
+	for k := 0; k < n; k++ {
+		row := b.rowView(k)
+		for i := 0; i < k; i++ {
+			lki := l.At(k, i)
+			if lki == 0 {
+				continue
+			}
+			prev := b.rowView(i)
+			for j := range row[:c] {
+				row[j] -= lki * prev[j]
+			}
+		}
+		for j := range row[:c] {
+			row[j] /= l.At(k, k)
+		}
+	}
+}
+
+// qrCov returns g*g' where g = q*inv(r) for the triangular factor r of f,
+// or g = inv(r) if q is nil.
+func qrCov(f QRFactor, q *Dense) *Dense {
+	n := len(f.rDiag)
+	g := newIdentity(n)
+	col := make([]float64, n)
+	for j := 0; j < n; j++ {
+		for i := range col {
+			col[i] = g.At(i, j)
+		}
+		f.solveRVec(col)
+		for i, v := range col {
+			g.Set(i, j, v)
+		}
+	}
+	if q != nil {
+		qg := &Dense{}
+		qg.Mul(q, g)
+		g = qg
+	}
+	cov := &Dense{}
+	cov.SymRankK(1, g, false, 0)
+	return cov
+}
+
+// residualNorms returns the 2-norms of the columns of a*x - b.
+func residualNorms(a, x, b Matrix) []float64 {
+	r := &Dense{}
+	r.Mul(a, x)
+	r.Sub(r, b)
+	m, k := r.Dims()
+	norms := make([]float64, k)
+	for j := range norms {
+		for i := 0; i < m; i++ {
+			norms[j] = math.Hypot(norms[j], r.At(i, j))
+		}
+	}
+	return norms
+}
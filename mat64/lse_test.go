@@ -0,0 +1,192 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+)
+
+func (s *S) TestLSE(c *check.C) {
+	for i, test := range []struct {
+		a, b, c, d *Dense
+	}{
+		{
+			a: NewDense(4, 3, []float64{
+				1, 2, 0,
+				0, 1, 1,
+				1, 0, 1,
+				2, 1, 3,
+			}),
+			b: NewDense(4, 1, []float64{1, 2, 3, 4}),
+			c: NewDense(1, 3, []float64{1, 1, 1}),
+			d: NewDense(1, 1, []float64{1}),
+		},
+		{
+			a: NewDense(5, 4, []float64{
+				1, 1, 1, 1,
+				1, 3, 1, 1,
+				1, -1, 3, 1,
+				1, 1, 1, 3,
+				1, 1, 1, -1,
+			}),
+			b: NewDense(5, 2, []float64{2, 0, 1, 1, 6, -2, 3, 5, 2, 0}),
+			c: NewDense(2, 4, []float64{
+				1, 1, 1, -1,
+				1, -1, 1, 1,
+			}),
+			d: NewDense(2, 2, []float64{1, 0, 3, 2}),
+		},
+		{
+			// Fewer observations than unknowns, made up for by
+			// the constraints.
+			a: NewDense(2, 3, []float64{
+				1, 2, 3,
+				-1, 0, 2,
+			}),
+			b: NewDense(2, 1, []float64{1, -1}),
+			c: NewDense(1, 3, []float64{0, 1, -1}),
+			d: NewDense(1, 1, []float64{0.5}),
+		},
+		{
+			a: NewDense(3, 2, []float64{
+				1, 0,
+				0, 1,
+				1, 1,
+			}),
+			b: NewDense(3, 1, []float64{1, 2, 3}),
+			c: NewDense(2, 2, []float64{
+				2, 1,
+				1, -1,
+			}),
+			d: NewDense(2, 1, []float64{4, -1}),
+		},
+	} {
+		res, err := LSE(test.a, test.b, test.c, test.d)
+		c.Assert(err, check.Equals, nil, check.Commentf("Test %d", i))
+
+		// Compare with the solution of the KKT system
+		//  [a'*a c'; c 0] [x; lambda] = [a'*b; d]
+		// whose inverse has the covariance as its leading block.
+		_, n := test.a.Dims()
+		p, _ := test.c.Dims()
+		var at, ct, ata, atb Dense
+		at.TCopy(test.a)
+		ct.TCopy(test.c)
+		ata.Mul(&at, test.a)
+		atb.Mul(&at, test.b)
+		var top, bot, kkt, rhs Dense
+		top.Augment(&ata, &ct)
+		bot.Augment(test.c, NewDense(p, p, nil))
+		kkt.Stack(&top, &bot)
+		rhs.Stack(&atb, test.d)
+		sol := Solve(&kkt, &rhs)
+		_, k := test.b.Dims()
+		want := &Dense{}
+		want.Submatrix(sol, 0, 0, n, k)
+		c.Check(res.X.EqualsApprox(want, 1e-12), check.Equals, true, check.Commentf("Test %d: got %v want %v", i, res.X, want))
+
+		var cx Dense
+		cx.Mul(test.c, res.X)
+		c.Check(cx.EqualsApprox(test.d, 1e-13), check.Equals, true, check.Commentf("Test %d", i))
+
+		cov := &Dense{}
+		cov.Submatrix(Inverse(&kkt), 0, 0, n, n)
+		c.Check(res.Cov.EqualsApprox(cov, 1e-12), check.Equals, true, check.Commentf("Test %d: got %v want %v", i, res.Cov, cov))
+
+		var r Dense
+		r.Mul(test.a, res.X)
+		r.Sub(&r, test.b)
+		for j, got := range res.ResidualNorm {
+			col := r.Col(nil, j)
+			var norm float64
+			for _, v := range col {
+				norm += v * v
+			}
+			c.Check(math.Abs(got-math.Sqrt(norm)) < 1e-13, check.Equals, true, check.Commentf("Test %d", i))
+		}
+	}
+
+	// Dependent constraints.
+	_, err := LSE(newIdentity(3), NewDense(3, 1, []float64{1, 2, 3}),
+		NewDense(2, 3, []float64{1, 1, 0, 2, 2, 0}), NewDense(2, 1, []float64{1, 2}))
+	c.Check(err, check.Equals, ErrSingular)
+
+	// The unknown in the null space of a is not fixed by c.
+	_, err = LSE(NewDense(2, 3, []float64{1, 0, 0, 0, 1, 0}), NewDense(2, 1, []float64{1, 2}),
+		NewDense(1, 3, []float64{1, 1, 0}), NewDense(1, 1, []float64{1}))
+	c.Check(err, check.Equals, ErrSingular)
+}
+
+func (s *S) TestGLS(c *check.C) {
+	for i, test := range []struct {
+		a, b, w *Dense
+	}{
+		{
+			a: NewDense(4, 2, []float64{
+				1, 1,
+				1, 2,
+				1, 3,
+				1, 4,
+			}),
+			b: NewDense(4, 1, []float64{1.1, 1.9, 3.2, 3.9}),
+			w: NewDense(4, 4, []float64{
+				1, 0, 0, 0,
+				0, 4, 0, 0,
+				0, 0, 0.25, 0,
+				0, 0, 0, 2,
+			}),
+		},
+		{
+			a: NewDense(5, 3, []float64{
+				1, 0.5, 2,
+				1, -1, 0,
+				1, 2, 1,
+				1, 0, -1,
+				1, 1, 1,
+			}),
+			b: NewDense(5, 2, []float64{1, 0, 2, 1, -1, 3, 0.5, 0.5, 2, -2}),
+			w: NewDense(5, 5, []float64{
+				2, 0.5, 0, 0, 0.1,
+				0.5, 3, 0.2, 0, 0,
+				0, 0.2, 1, 0.3, 0,
+				0, 0, 0.3, 2, 0.4,
+				0.1, 0, 0, 0.4, 1,
+			}),
+		},
+	} {
+		res, err := GLS(test.a, test.b, Cholesky(DenseCopyOf(test.w)))
+		c.Assert(err, check.Equals, nil, check.Commentf("Test %d", i))
+
+		// Compare with the normal equations
+		//  a'*inv(w)*a*x = a'*inv(w)*b.
+		var at, atw, atwa, atwb Dense
+		at.TCopy(test.a)
+		wi := Inverse(test.w)
+		atw.Mul(&at, wi)
+		atwa.Mul(&atw, test.a)
+		atwb.Mul(&atw, test.b)
+		want := Solve(&atwa, &atwb)
+		c.Check(res.X.EqualsApprox(want, 1e-12), check.Equals, true, check.Commentf("Test %d: got %v want %v", i, res.X, want))
+		c.Check(res.Cov.EqualsApprox(Inverse(&atwa), 1e-12), check.Equals, true, check.Commentf("Test %d", i))
+
+		var r, wr Dense
+		r.Mul(test.a, res.X)
+		r.Sub(&r, test.b)
+		wr.Mul(wi, &r)
+		for j, got := range res.ResidualNorm {
+			var norm float64
+			for k := 0; k < len(wr.Col(nil, j)); k++ {
+				norm += r.At(k, j) * wr.At(k, j)
+			}
+			c.Check(math.Abs(got-math.Sqrt(norm)) < 1e-12, check.Equals, true, check.Commentf("Test %d", i))
+		}
+	}
+
+	_, err := GLS(newIdentity(2), NewDense(2, 1, []float64{1, 2}), Cholesky(NewDense(2, 2, []float64{1, 2, 2, 1})))
+	c.Check(err, check.Equals, ErrNotPosDef)
+	_, err = GLS(NewDense(3, 2, []float64{1, 2, 2, 4, 3, 6}), NewDense(3, 1, []float64{1, 2, 3}), Cholesky(newIdentity(3)))
+	c.Check(err, check.Equals, ErrSingular)
+}
@@ -13,6 +13,10 @@ type LUFactors struct {
 	LU    *Dense
 	Pivot []int
 	Sign  int
+
+	// norm is the 1-norm of the factorized matrix, recorded by LU and
+	// LUGaussian for RCond. It is zero for factors built as literals.
+	norm float64
 }
 
 // LUD performs an LU Decomposition for an m-by-n matrix a.
@@ -30,6 +34,7 @@ type LUFactors struct {
 func LU(a *Dense) LUFactors {
 	// Use a "left-looking", dot-product, Crout/Doolittle algorithm.
 	m, n := a.Dims()
+	norm := norm1(a)
 	lu := a
 
 	piv := make([]int, m)
@@ -87,7 +92,7 @@ func LU(a *Dense) LUFactors {
 		}
 	}
 
-	return LUFactors{lu, piv, sign}
+	return LUFactors{lu, piv, sign, norm}
 }
 
 // LUGaussian performs an LU Decomposition for an m-by-n matrix a using Gaussian elimination.
@@ -107,6 +112,7 @@ func LU(a *Dense) LUFactors {
 func LUGaussian(a *Dense) LUFactors {
 	// Initialize.
 	m, n := a.Dims()
+	norm := norm1(a)
 	lu := a
 
 	piv := make([]int, m)
@@ -147,7 +153,7 @@ func LUGaussian(a *Dense) LUFactors {
 		}
 	}
 
-	return LUFactors{lu, piv, sign}
+	return LUFactors{lu, piv, sign, norm}
 }
 
 // IsSingular returns whether the the upper triangular factor and hence a is
@@ -201,7 +207,7 @@ func (f LUFactors) Det() float64 {
 	lu, sign := f.LU, f.Sign
 	m, n := lu.Dims()
 	if m != n {
-		panic(ErrSquare)
+		panic(opError(ErrSquare, "LUFactors.Det", lu))
 	}
 	d := float64(sign)
 	for j := 0; j < n; j++ {
@@ -218,10 +224,10 @@ func (f LUFactors) Solve(b *Dense) (x *Dense) {
 	m, n := lu.Dims()
 	bm, bn := b.Dims()
 	if bm != m {
-		panic(ErrShape)
+		panic(opError(ErrShape, "LUFactors.Solve", lu, b))
 	}
 	if f.IsSingular() {
-		panic("mat64: matrix is singular")
+		panic(opError(ErrSingular, "LUFactors.Solve", lu, b))
 	}
 
 	// Copy right hand side with pivoting
@@ -252,6 +258,102 @@ func (f LUFactors) Solve(b *Dense) (x *Dense) {
 	return x
 }
 
+// SolveTrans computes the solution of a'.x = b where b has as many rows as a,
+// using the factors of a rather than factorizing its transpose. SolveTrans will
+// panic if a is singular. The matrix b is overwritten by x.
+func (f LUFactors) SolveTrans(b *Dense) (x *Dense) {
+	lu := f.LU
+	m, _ := lu.Dims()
+	if bm, _ := b.Dims(); bm != m {
+		panic(opError(ErrShape, "LUFactors.SolveTrans", lu, b))
+	}
+	if f.IsSingular() {
+		panic(opError(ErrSingular, "LUFactors.SolveTrans", lu, b))
+	}
+	solveColumns(b, f.solveTransVec)
+	return b
+}
+
+// RCond returns an estimate of the reciprocal of the 1-norm condition number of
+// the square matrix a decomposed into lu, 1/(norm(a, 1)*norm(inv(a), 1)). The
+// norm of inv(a) is estimated in O(n^2) operations from solutions with the
+// factors by the method of Hager and Higham. RCond returns zero if a is singular.
+//
+// The norm of a is recorded when the factors are computed by LU or LUGaussian,
+// since a is overwritten by the factorization. RCond panics with ErrNoNorm for
+// nonsingular factors that were not, such as a literal LUFactors.
+func (f LUFactors) RCond() float64 {
+	m, n := f.LU.Dims()
+	if m != n {
+		panic(opError(ErrSquare, "LUFactors.RCond", f.LU))
+	}
+	if f.IsSingular() {
+		return 0
+	}
+	if f.norm == 0 {
+		panic(opError(ErrNoNorm, "LUFactors.RCond", f.LU))
+	}
+	return 1 / (f.norm * normEst1(n, f.solveVec, f.solveTransVec))
+}
+
+// SolveRefine returns the solution x of a.x = b for the square matrix a decomposed
+// into lu, improving each column of x by iterative refinement while its
+// componentwise backward error is above epsilon and at least halves with each
+// step, up to five steps. The accompanying SolveInfo reports the backward
+// errors, a forward error bound and the estimated reciprocal condition number
+// of a. The matrices a and b are not altered.
+//
+// SolveRefine returns ErrSingular and a nil x if a is singular, and
+// ErrIllConditioned along with x if the reciprocal condition number is less
+// than epsilon.
+func (f LUFactors) SolveRefine(a, b *Dense) (x *Dense, info SolveInfo, err error) {
+	return solveRefine(f, f.RCond(), a, b)
+}
+
+// solveVec overwrites x with the solution of a*y = x.
+func (f LUFactors) solveVec(x []float64) {
+	lu := f.LU
+	n := len(x)
+	y := make([]float64, n)
+	for i, p := range f.Pivot {
+		y[i] = x[p]
+	}
+	for i := 0; i < n; i++ {
+		for k := 0; k < i; k++ {
+			y[i] -= lu.At(i, k) * y[k]
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		for k := i + 1; k < n; k++ {
+			y[i] -= lu.At(i, k) * y[k]
+		}
+		y[i] /= lu.At(i, i)
+	}
+	copy(x, y)
+}
+
+// solveTransVec overwrites x with the solution of a'*y = x.
+func (f LUFactors) solveTransVec(x []float64) {
+	lu := f.LU
+	n := len(x)
+	w := make([]float64, n)
+	copy(w, x)
+	for i := 0; i < n; i++ {
+		for k := 0; k < i; k++ {
+			w[i] -= lu.At(k, i) * w[k]
+		}
+		w[i] /= lu.At(i, i)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for k := i + 1; k < n; k++ {
+			w[i] -= lu.At(k, i) * w[k]
+		}
+	}
+	for i, p := range f.Pivot {
+		x[p] = w[i]
+	}
+}
+
 func pivotRows(a *Dense, piv []int) *Dense {
 	visit := make([]bool, len(piv))
 	_, n := a.Dims()
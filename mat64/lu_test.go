@@ -129,3 +129,53 @@ func (s *S) TestLUDGaussian(c *check.C) {
 		c.Check(t.a.EqualsApprox(eye(), 1e-12), check.Equals, true)
 	}
 }
+
+// hilbert returns the n-by-n Hilbert matrix, which is symmetric positive
+// definite and increasingly ill-conditioned with n.
+func hilbert(n int) *Dense {
+	h := NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			h.Set(i, j, 1/float64(i+j+1))
+		}
+	}
+	return h
+}
+
+// rcond returns the reciprocal 1-norm condition number of a computed from its
+// explicit inverse.
+func rcond(a *Dense) float64 {
+	return 1 / (norm1(a) * norm1(Inverse(a)))
+}
+
+func (s *S) TestLURCond(c *check.C) {
+	for i, a := range []*Dense{
+		NewDense(1, 1, []float64{-4}),
+		NewDense(3, 3, []float64{
+			1, 2, 3,
+			4, 5, 6,
+			7, 8, 10,
+		}),
+		NewDense(4, 4, []float64{
+			4, -1, 0.5, 2,
+			1, 1e-3, 3, 0,
+			-2, 0, 1, 7,
+			0.5, 6, 0, 1,
+		}),
+		hilbert(6),
+		hilbert(10),
+	} {
+		want := rcond(a)
+		got := LU(DenseCopyOf(a)).RCond()
+
+		// The estimate of norm(inv(a), 1) is a lower bound and is
+		// rarely far from the true value.
+		c.Check(got >= want*(1-1e-6) && got <= 3*want, check.Equals, true,
+			check.Commentf("Test %d: got %v want %v", i, got, want))
+	}
+
+	c.Check(LU(NewDense(2, 2, []float64{1, 2, 2, 4})).RCond(), check.Equals, 0.)
+
+	lit := LUFactors{LU: NewDense(1, 1, []float64{2}), Pivot: []int{0}, Sign: 1}
+	c.Check(func() { lit.RCond() }, check.PanicMatches, ".*do not record the norm.*")
+}
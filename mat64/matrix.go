@@ -11,6 +11,9 @@
 package mat64
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/gonum/blas"
 )
 
@@ -69,10 +72,10 @@ type RowViewer interface {
 	RowView(r int) []float64
 }
 
-// A ColViewer can return a slice of float64 reflecting a column that is backed by the matrix
-// data.
+// A ColViewer can return a strided vector view reflecting a column that is backed by the
+// matrix data.
 type ColViewer interface {
-	ColView(c int) []float64
+	ColView(c int) VecView
 }
 
 // A Cloner can make a copy of a into the receiver, overwriting the previous value of the
@@ -97,6 +100,14 @@ type Viewer interface {
 	View(a Matrix, i, j, r, c int)
 }
 
+// A Subvectorer can extract a view of the column vector a into the receiver, starting
+// at element i and extending n elements. If i is out of range, or n extends beyond the
+// end of the vector Subvector will panic with ErrIndexOutOfRange. As for View, changes
+// in the elements of the subvector are reflected in the original and vice versa.
+type Subvectorer interface {
+	Subvector(a Matrix, i, n int)
+}
+
 // A Submatrixer can extract a copy of submatrix from a into the receiver, starting at row i,
 // column j and extending r rows and c columns. If i or j are out of range, or r or c extend
 // beyond the bounds of the matrix Submatrix will panic with ErrIndexOutOfRange. There is no
@@ -163,6 +174,13 @@ type ElemMuler interface {
 	MulElem(a, b Matrix)
 }
 
+// An ElemDiver can perform element-wise division of the matrix represented by a by that
+// represented by b, placing the result in the receiver. DivElem will panic if the two
+// matrices do not have the same shape.
+type ElemDiver interface {
+	DivElem(a, b Matrix)
+}
+
 // An Equaler can compare the matrices represented by b and the receiver. Matrices with non-equal shapes
 // are not equal.
 type Equaler interface {
@@ -221,6 +239,17 @@ type Applyer interface {
 	Apply(f ApplyFunc, a Matrix)
 }
 
+// An ApplyBinaryFunc takes a row/column index and the corresponding element values of two
+// matrices and returns some function of that tuple.
+type ApplyBinaryFunc func(r, c int, a, b float64) float64
+
+// A BinaryApplyer can apply an ApplyBinaryFunc f to each pair of corresponding elements of
+// the matrices represented by a and b, placing the resulting matrix in the receiver.
+// ApplyBinary will panic if the two matrices do not have the same shape.
+type BinaryApplyer interface {
+	ApplyBinary(f ApplyBinaryFunc, a, b Matrix)
+}
+
 // A Tracer can return the trace of the matrix represented by the receiver. Trace will panic if the
 // matrix is not square.
 type Tracer interface {
@@ -261,7 +290,7 @@ func (b RawMatrix) Matrix(c Mutable) {
 		return
 	}
 	if rows, cols := c.Dims(); rows != b.Rows || cols != b.Cols {
-		panic(ErrShape)
+		panic(dimsError(ErrShape, "RawMatrix.Matrix", [2]int{b.Rows, b.Cols}, [2]int{rows, cols}))
 	}
 	if b.Order == blas.ColMajor {
 		for col := 0; col < b.Cols; col++ {
@@ -276,7 +305,7 @@ func (b RawMatrix) Matrix(c Mutable) {
 			}
 		}
 	} else {
-		panic("matrix: illegal order")
+		panic(ErrIllegalOrder)
 	}
 }
 
@@ -300,19 +329,32 @@ func Det(a Matrix) float64 {
 	return LU(DenseCopyOf(a)).Det()
 }
 
-// Inverse returns the inverse or pseudoinverse of the matrix a.
+// Inverse returns the inverse of the matrix a if it is square and non-singular,
+// and otherwise its Moore-Penrose pseudoinverse computed by singular value
+// decomposition.
 func Inverse(a Matrix) *Dense {
-	m, _ := a.Dims()
-	d := make([]float64, m*m)
-	for i := 0; i < m*m; i += m + 1 {
-		d[i] = 1
+	m, n := a.Dims()
+	if m == n {
+		lu := LU(DenseCopyOf(a))
+		if !lu.IsSingular() {
+			d := make([]float64, m*m)
+			for i := 0; i < m*m; i += m + 1 {
+				d[i] = 1
+			}
+			eye := NewDense(m, m, d)
+			return lu.Solve(eye)
+		}
 	}
-	eye := NewDense(m, m, d)
-	return Solve(a, eye)
+	return SVDJobs(DenseCopyOf(a), epsilon, small, SVDThin, SVDThin, false).PseudoInverse(epsilon)
 }
 
-// Solve returns a matrix x that satisfies ax = b.
+// Solve returns a matrix x that satisfies ax = b. If a is a Transpose, the
+// wrapped matrix is factorized and the transposed system solved from its
+// factors.
 func Solve(a, b Matrix) (x *Dense) {
+	if t, ok := a.(Transpose); ok {
+		return solveTrans(t.Matrix, b)
+	}
 	switch m, n := a.Dims(); {
 	case m == n:
 		return LU(DenseCopyOf(a)).Solve(DenseCopyOf(b))
@@ -328,16 +370,46 @@ func Solve(a, b Matrix) (x *Dense) {
 	}
 }
 
+// SolveEquilibrated returns a matrix x that satisfies ax = b as for Solve, except
+// that a square a is factorized by LUEquilibrated, so that a badly scaled a is
+// scaled by rows and columns before its pivots are chosen. Non-square systems
+// are solved as by Solve.
+func SolveEquilibrated(a, b Matrix) (x *Dense) {
+	if t, ok := a.(Transpose); ok {
+		if m, n := t.Dims(); m == n {
+			return LUEquilibrated(DenseCopyOf(t.Matrix)).SolveTrans(DenseCopyOf(b))
+		}
+		return solveTrans(t.Matrix, b)
+	}
+	if m, n := a.Dims(); m == n {
+		return LUEquilibrated(DenseCopyOf(a)).Solve(DenseCopyOf(b))
+	}
+	return Solve(a, b)
+}
+
+// solveTrans returns the solution of a'.x = b as for Solve, factorizing a
+// rather than its transpose.
+func solveTrans(a, b Matrix) (x *Dense) {
+	switch m, n := a.Dims(); {
+	case m == n:
+		return LU(DenseCopyOf(a)).SolveTrans(DenseCopyOf(b))
+	case m > n:
+		return QR(DenseCopyOf(a)).SolveTrans(DenseCopyOf(b))
+	default:
+		return LQ(DenseCopyOf(a)).SolveTrans(DenseCopyOf(b))
+	}
+}
+
 // A Panicker is a function that may panic.
 type Panicker func()
 
-// Maybe will recover a panic with a type matrix.Error from fn, and return this error.
-// Any other error is re-panicked.
+// Maybe will recover a panic with a type matrix.Error or *OpError from fn, and return this
+// error. Any other error is re-panicked.
 func Maybe(fn Panicker) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			var ok bool
-			if err, ok = r.(Error); ok {
+			if err, ok = recoverable(r); ok {
 				return
 			}
 			panic(r)
@@ -350,12 +422,12 @@ func Maybe(fn Panicker) (err error) {
 // A FloatPanicker is a function that returns a float64 and may panic.
 type FloatPanicker func() float64
 
-// MaybeFloat will recover a panic with a type matrix.Error from fn, and return this error.
-// Any other error is re-panicked.
+// MaybeFloat will recover a panic with a type matrix.Error or *OpError from fn, and return
+// this error. Any other error is re-panicked.
 func MaybeFloat(fn FloatPanicker) (f float64, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			if e, ok := r.(Error); ok {
+			if e, ok := recoverable(r); ok {
 				err = e
 				return
 			}
@@ -365,6 +437,17 @@ func MaybeFloat(fn FloatPanicker) (f float64, err error) {
 	return fn(), nil
 }
 
+// recoverable returns the panic value r as an error if it is a matrix package error.
+func recoverable(r interface{}) (error, bool) {
+	switch e := r.(type) {
+	case Error:
+		return e, true
+	case *OpError:
+		return e, true
+	}
+	return nil, false
+}
+
 // Must can be used to wrap a function returning an error.
 // If the returned error is not nil, Must will panic.
 func Must(err error) {
@@ -378,6 +461,65 @@ type Error string
 
 func (err Error) Error() string { return string(err) }
 
+// OpError is an Error with the context in which it arose. It unwraps to its Err
+// field, so errors.Is(err, ErrShape) reports whether err is a shape error whether
+// or not it carries context. OpErrors can be recovered by Maybe wrappers.
+type OpError struct {
+	// Op is the operation that failed, such as "Dense.Mul".
+	Op string
+
+	// Dims holds the rows and columns of the operands of Op, with the
+	// receiver of a method first.
+	Dims [][2]int
+
+	// Index holds the offending index for ErrIndexOutOfRange.
+	Index []int
+
+	// Err is the underlying error.
+	Err Error
+}
+
+func (e *OpError) Error() string {
+	s := fmt.Sprintf("%s: %s", e.Err, e.Op)
+	if e.Dims != nil {
+		d := make([]string, len(e.Dims))
+		for i, rc := range e.Dims {
+			d[i] = fmt.Sprintf("%dx%d", rc[0], rc[1])
+		}
+		s += "(" + strings.Join(d, ", ") + ")"
+	}
+	if e.Index != nil {
+		s += fmt.Sprintf(" at index %v", e.Index)
+	}
+	return s
+}
+
+// Unwrap returns the underlying Error.
+func (e *OpError) Unwrap() error { return e.Err }
+
+// opError returns an OpError for err in the operation op on the given operands.
+func opError(err Error, op string, operands ...Matrix) *OpError {
+	dims := make([][2]int, len(operands))
+	for i, m := range operands {
+		dims[i][0], dims[i][1] = m.Dims()
+	}
+	return dimsError(err, op, dims...)
+}
+
+// dimsError returns an OpError for err in the operation op on operands with
+// the given dimensions, for operands that are not held as matrices.
+func dimsError(err Error, op string, dims ...[2]int) *OpError {
+	return &OpError{Op: op, Err: err, Dims: dims}
+}
+
+// indexError returns an OpError for the out of range index in the operation
+// op on the matrix m.
+func indexError(op string, m Matrix, index ...int) *OpError {
+	e := opError(ErrIndexOutOfRange, op, m)
+	e.Index = index
+	return e
+}
+
 const (
 	ErrIndexOutOfRange = Error("mat64: index out of range")
 	ErrZeroLength      = Error("mat64: zero length in matrix definition")
@@ -391,6 +533,18 @@ const (
 	ErrPivot           = Error("mat64: malformed pivot list")
 	ErrIllegalOrder    = Error("mat64: illegal order")
 	ErrNoEngine        = Error("mat64: no blas engine registered: call Register()")
+	ErrNoConvergence   = Error("mat64: iteration did not converge")
+	ErrNoVectors       = Error("mat64: singular vectors not formed")
+	ErrNegativeEigen   = Error("mat64: matrix has a negative real eigenvalue")
+	ErrRepeatedEigen   = Error("mat64: matrix has repeated eigenvalues")
+	ErrNoStabilizing   = Error("mat64: no stabilizing solution")
+	ErrIllConditioned  = Error("mat64: matrix singular to working precision")
+	ErrBounds          = Error("mat64: lower bound exceeds upper bound")
+	ErrNotPosDef       = Error("mat64: matrix not symmetric positive definite")
+	ErrRankDeficient   = Error("mat64: matrix is rank deficient")
+	ErrWhich           = Error("mat64: illegal eigenvalue selection")
+	ErrNoNorm          = Error("mat64: factors do not record the norm of the matrix")
+	ErrNotOptimal      = Error("mat64: optimality conditions not satisfied")
 )
 
 func min(a, b int) int {
@@ -415,3 +569,16 @@ func use(f []float64, l int) []float64 {
 	}
 	return make([]float64, l)
 }
+
+// useZero returns a float64 slice with l zeroed elements, using f if it
+// has the necessary capacity, otherwise creating a new slice.
+func useZero(f []float64, l int) []float64 {
+	if l <= cap(f) {
+		f = f[:l]
+		for i := range f {
+			f[i] = 0
+		}
+		return f
+	}
+	return make([]float64, l)
+}
@@ -0,0 +1,344 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+)
+
+// States of the variables of a bounded least squares problem.
+const (
+	varFree   = iota // in the factorization and strictly within its bounds
+	varLower         // held at its lower bound
+	varUpper         // held at its upper bound
+	varParked        // unbounded and held at zero until it enters
+)
+
+// NNLS returns the solution x of the nonnegative least squares problem
+//  min ||a*x - b|| subject to x >= 0
+// for an m-by-n matrix a and an m-by-1 matrix b, computed by the active set
+// method of Lawson and Hanson, "Solving Least Squares Problems", 1974. The
+// active set holds the indices of the elements of x that are held at zero by
+// their constraint.
+//
+// NNLS returns ErrNoConvergence if the iteration limit of 3*(n+1) steps is
+// reached, and ErrNotOptimal if the columns of a are so nearly dependent that
+// no variable able to decrease the residual can be released from its
+// constraint. In both cases x is the feasible point reached.
+func NNLS(a, b Matrix) (x *Dense, active []int, err error) {
+	_, n := a.Dims()
+	lo := make([]float64, n)
+	hi := make([]float64, n)
+	for j := range hi {
+		hi[j] = math.Inf(1)
+	}
+	x, active, _, err = BoundedLSQ(a, b, lo, hi)
+	return x, active, err
+}
+
+// BoundedLSQ returns the solution x of the bounded-variable least squares
+// problem
+//  min ||a*x - b|| subject to lo <= x <= hi
+// for an m-by-n matrix a and an m-by-1 matrix b. Elements of lo and hi may be
+// infinite. The lower and upper active sets hold the indices of the elements
+// of x held at their lower and upper bounds by their constraints.
+//
+// The problem is solved by the Lawson-Hanson active set method extended to
+// upper bounds. The least squares problem on the free variables is solved at
+// each step from a QR factorization of the free columns, updated by Givens
+// rotations as variables enter and leave the free set. As in the method of
+// Lawson and Hanson the rotations are applied to a and b rather than
+// accumulated, so the orthogonal factor is never formed.
+//
+// BoundedLSQ will panic with ErrBounds if lo[j] > hi[j] for any j. It returns
+// ErrNoConvergence if the iteration limit of 3*(n+1) steps is reached and
+// ErrNotOptimal if the optimality conditions fail but every variable that
+// could enter the free set is numerically dependent on it, as for NNLS; x is
+// then the feasible point reached.
+func BoundedLSQ(a, b Matrix, lo, hi []float64) (x *Dense, lower, upper []int, err error) {
+	m, n := a.Dims()
+	if bm, bn := b.Dims(); bm != m || bn != 1 {
+		panic(opError(ErrShape, "BoundedLSQ", a, b))
+	}
+	if len(lo) != n || len(hi) != n {
+		panic(opError(ErrShape, "BoundedLSQ", a, b))
+	}
+	for j := range lo {
+		if lo[j] > hi[j] {
+			panic(ErrBounds)
+		}
+	}
+
+	ad := DenseCopyOf(a)
+	bv := make([]float64, m)
+	for i := range bv {
+		bv[i] = b.At(i, 0)
+	}
+	xv, state, err := boundedLSQ(ad, bv, lo, hi)
+
+	for j, st := range state {
+		switch st {
+		case varLower:
+			lower = append(lower, j)
+		case varUpper:
+			upper = append(upper, j)
+		}
+	}
+	return NewDense(n, 1, xv), lower, upper, err
+}
+
+// boundedLSQ solves the bounded least squares problem for a and b, returning
+// the solution and the state of each variable. The matrix a is overwritten.
+func boundedLSQ(a *Dense, b, lo, hi []float64) (x []float64, state []int, err error) {
+	m, n := a.Dims()
+	x = make([]float64, n)
+	state = make([]int, n)
+	for j := range x {
+		switch {
+		case !math.IsInf(lo[j], -1):
+			x[j], state[j] = lo[j], varLower
+		case !math.IsInf(hi[j], 1):
+			x[j], state[j] = hi[j], varUpper
+		default:
+			state[j] = varParked
+		}
+	}
+
+	// residual sets r to q'*(b - a*x) from the rotated a and b held by u,
+	// leaving out the free variables unless withFree is true. The gradient
+	// a'*(b - a*x) is unchanged by the rotations.
+	u := newQRUpdate(a, b)
+	r := make([]float64, m)
+	residual := func(withFree bool) {
+		copy(r, u.qtb)
+		for j, xj := range x {
+			if xj == 0 || !withFree && state[j] == varFree {
+				continue
+			}
+			for i := range r {
+				r[i] -= a.At(i, j) * xj
+			}
+		}
+	}
+	residual(true)
+	var bnorm float64
+	for i := range b {
+		bnorm = math.Max(bnorm, math.Max(math.Abs(b[i]), math.Abs(r[i])))
+	}
+	tol := 10 * epsilon * float64(max(m, n)) * norm1(a) * bnorm
+
+	skip := make([]bool, n)
+	maxIter := 3 * (n + 1)
+	for iter := 0; ; {
+		// Find the bound variable whose release most decreases the
+		// residual, from the gradient w = a'*(b - a*x).
+		residual(true)
+		t, best := -1, tol
+		stuck := false
+		for j, st := range state {
+			if st == varFree || lo[j] == hi[j] {
+				continue
+			}
+			var w float64
+			for i, v := range r {
+				w += a.At(i, j) * v
+			}
+			switch st {
+			case varUpper:
+				w = -w
+			case varParked:
+				w = math.Abs(w)
+			}
+			if skip[j] {
+				stuck = stuck || w > tol
+				continue
+			}
+			if w > best {
+				t, best = j, w
+			}
+		}
+		if t < 0 {
+			if stuck {
+				// Every variable that could decrease the residual
+				// was rejected as dependent on the free variables.
+				return x, state, ErrNotOptimal
+			}
+			return x, state, nil
+		}
+		if !u.add(t) {
+			skip[t] = true
+			continue
+		}
+		prev := state[t]
+		state[t] = varFree
+
+		for first := true; ; first = false {
+			iter++
+			if iter > maxIter {
+				for p := len(u.cols) - 1; p >= 0; p-- {
+					j := u.cols[p]
+					if x[j] == lo[j] {
+						state[j] = varLower
+					} else if x[j] == hi[j] {
+						state[j] = varUpper
+					}
+				}
+				return x, state, ErrNoConvergence
+			}
+
+			// Solve the unconstrained problem on the free variables
+			// with the bound variables fixed.
+			residual(false)
+			z := u.solve(r)
+
+			// Rounding can make the entering variable move against
+			// its gradient; keep it bound until another step succeeds.
+			if first {
+				zt := z[len(z)-1]
+				if prev == varLower && zt <= x[t] || prev == varUpper && zt >= x[t] {
+					u.remove(len(u.cols) - 1)
+					state[t] = prev
+					skip[t] = true
+					break
+				}
+			}
+
+			// Move towards z as far as the bounds allow.
+			alpha, hit := 1.0, -1
+			for p, j := range u.cols {
+				var s float64
+				switch {
+				case z[p] < lo[j]:
+					s = (x[j] - lo[j]) / (x[j] - z[p])
+				case z[p] > hi[j]:
+					s = (hi[j] - x[j]) / (z[p] - x[j])
+				default:
+					continue
+				}
+				if s < alpha {
+					alpha, hit = s, p
+				}
+			}
+			for p, j := range u.cols {
+				x[j] += alpha * (z[p] - x[j])
+			}
+			if hit < 0 {
+				for j := range skip {
+					skip[j] = false
+				}
+				break
+			}
+
+			// Bind the variables that have reached a bound.
+			for p := len(u.cols) - 1; p >= 0; p-- {
+				j := u.cols[p]
+				switch {
+				case x[j] <= lo[j] || p == hit && z[p] < lo[j]:
+					x[j], state[j] = lo[j], varLower
+				case x[j] >= hi[j] || p == hit && z[p] > hi[j]:
+					x[j], state[j] = hi[j], varUpper
+				default:
+					continue
+				}
+				u.remove(p)
+			}
+		}
+	}
+}
+
+// qrUpdate holds the QR factorization of a selection of the columns of the
+// m-by-n matrix a, a[:, cols] = q*r. Rather than forming q, the rotations that
+// update the factorization as columns are added and removed are applied to a
+// and to the right-hand side b in place, so that a holds q'*a, with r in the
+// leading rows of the selected columns, and qtb holds q'*b.
+type qrUpdate struct {
+	a    *Dense
+	qtb  []float64
+	cols []int
+}
+
+// newQRUpdate returns an empty factorization of the columns of a with the
+// right-hand side b. The matrix a is overwritten by later updates.
+func newQRUpdate(a *Dense, b []float64) *qrUpdate {
+	return &qrUpdate{a: a, qtb: append([]float64(nil), b...)}
+}
+
+// add appends column j of a to the factorization. It returns false and
+// leaves the factorization unchanged if the column is numerically dependent
+// on those already present.
+func (u *qrUpdate) add(j int) bool {
+	m, _ := u.a.Dims()
+	k := len(u.cols)
+	if k == m {
+		return false
+	}
+
+	var norm, tail float64
+	for i := 0; i < m; i++ {
+		norm = math.Hypot(norm, u.a.At(i, j))
+		if i >= k {
+			tail = math.Hypot(tail, u.a.At(i, j))
+		}
+	}
+	if tail <= float64(m)*epsilon*norm {
+		return false
+	}
+
+	for i := m - 1; i > k; i-- {
+		c, s, _ := givens(u.a.At(i-1, j), u.a.At(i, j))
+		u.rotate(i-1, i, c, s)
+		u.a.Set(i, j, 0)
+	}
+	u.cols = append(u.cols, j)
+	return true
+}
+
+// remove deletes the p-th column of the factorization and restores r to
+// upper triangular form.
+func (u *qrUpdate) remove(p int) {
+	u.cols = append(u.cols[:p], u.cols[p+1:]...)
+	for l := p; l < len(u.cols); l++ {
+		j := u.cols[l]
+		c, s, _ := givens(u.a.At(l, j), u.a.At(l+1, j))
+		u.rotate(l, l+1, c, s)
+		u.a.Set(l+1, j, 0)
+	}
+}
+
+// rotate applies the rotation with cosine c and sine s to rows i and j of a
+// and of qtb.
+func (u *qrUpdate) rotate(i, j int, c, s float64) {
+	ri, rj := u.a.rowView(i), u.a.rowView(j)
+	for l, x := range ri {
+		y := rj[l]
+		ri[l], rj[l] = c*x+s*y, -s*x+c*y
+	}
+	x, y := u.qtb[i], u.qtb[j]
+	u.qtb[i], u.qtb[j] = c*x+s*y, -s*x+c*y
+}
+
+// solve returns the least squares solution z of a[:, cols]*z = q*qtr, given
+// the transformed right-hand side qtr.
+func (u *qrUpdate) solve(qtr []float64) []float64 {
+	k := len(u.cols)
+	z := make([]float64, k)
+	copy(z, qtr)
+	for i := k - 1; i >= 0; i-- {
+		for h := i + 1; h < k; h++ {
+			z[i] -= u.a.At(i, u.cols[h]) * z[h]
+		}
+		z[i] /= u.a.At(i, u.cols[i])
+	}
+	return z
+}
+
+// givens returns the cosine and sine of the rotation taking (a, b) to (h, 0).
+func givens(a, b float64) (c, s, h float64) {
+	h = math.Hypot(a, b)
+	if h == 0 {
+		return 1, 0, 0
+	}
+	return a / h, b / h, h
+}
@@ -0,0 +1,221 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+)
+
+// bruteBoundedLSQ solves the bounded least squares problem by trying every
+// assignment of the variables to their lower bound, upper bound or the free
+// set, returning the feasible candidate with the smallest residual.
+func bruteBoundedLSQ(a *Dense, b []float64, lo, hi []float64) (best []float64) {
+	m, n := a.Dims()
+	bestRes := math.Inf(1)
+	state := make([]int, n)
+	var try func(j int)
+	try = func(j int) {
+		if j < n {
+			for _, st := range []int{varFree, varLower, varUpper} {
+				if st == varLower && math.IsInf(lo[j], -1) || st == varUpper && math.IsInf(hi[j], 1) {
+					continue
+				}
+				state[j] = st
+				try(j + 1)
+			}
+			return
+		}
+
+		x := make([]float64, n)
+		rhs := NewDense(m, 1, append([]float64(nil), b...))
+		var free []int
+		for j, st := range state {
+			switch st {
+			case varFree:
+				free = append(free, j)
+				continue
+			case varLower:
+				x[j] = lo[j]
+			case varUpper:
+				x[j] = hi[j]
+			}
+			for i := 0; i < m; i++ {
+				rhs.Set(i, 0, rhs.At(i, 0)-a.At(i, j)*x[j])
+			}
+		}
+		if len(free) > m {
+			return
+		}
+		if len(free) > 0 {
+			af := NewDense(m, len(free), nil)
+			for p, j := range free {
+				for i := 0; i < m; i++ {
+					af.Set(i, p, a.At(i, j))
+				}
+			}
+			f := QR(af)
+			if !f.IsFullRank() {
+				return
+			}
+			z := f.Solve(rhs)
+			for p, j := range free {
+				x[j] = z.At(p, 0)
+				if x[j] < lo[j]-1e-12 || x[j] > hi[j]+1e-12 {
+					return
+				}
+			}
+		}
+		var res float64
+		for i := 0; i < m; i++ {
+			v := b[i]
+			for j := 0; j < n; j++ {
+				v -= a.At(i, j) * x[j]
+			}
+			res += v * v
+		}
+		if res < bestRes {
+			best, bestRes = x, res
+		}
+	}
+	try(0)
+	return best
+}
+
+func (s *S) TestNNLS(c *check.C) {
+	// A tall problem, for which the orthogonal factor is never formed.
+	tall := NewDense(40, 3, nil)
+	tallB := make([]float64, 40)
+	for i := range tallB {
+		for j := 0; j < 3; j++ {
+			tall.Set(i, j, math.Cos(float64(i*(j+1))))
+		}
+		tallB[i] = tall.At(i, 0) - 0.5*tall.At(i, 1) + 2*tall.At(i, 2) + 0.1*math.Sin(float64(i))
+	}
+
+	for i, test := range []struct {
+		a      *Dense
+		b      []float64
+		active []int
+	}{
+		{
+			a:      newIdentity(3),
+			b:      []float64{1, -2, 3},
+			active: []int{1},
+		},
+		{
+			a: NewDense(5, 3, []float64{
+				1, 2, 0.5,
+				0.3, 1, 2,
+				2, 0.1, 1,
+				1, 1, 1,
+				0.5, 3, 0.2,
+			}),
+			b:      []float64{3, -1, 2, 1, 4},
+			active: []int{2},
+		},
+		{
+			a: NewDense(4, 4, []float64{
+				1, -1, 2, 0,
+				0, 3, -1, 1,
+				2, 1, 0, -2,
+				1, 0, 1, 1,
+			}),
+			b:      []float64{1, 2, 3, 4},
+			active: nil,
+		},
+		{
+			a: NewDense(3, 4, []float64{
+				1, 2, -1, 0.5,
+				0, 1, 1, -2,
+				2, -1, 0, 1,
+			}),
+			b: []float64{-1, -2, 0.5},
+		},
+		{
+			a: tall,
+			b: tallB,
+		},
+	} {
+		m, n := test.a.Dims()
+		x, active, err := NNLS(test.a, NewDense(m, 1, test.b))
+		c.Assert(err, check.Equals, nil, check.Commentf("Test %d", i))
+		want := bruteBoundedLSQ(test.a, test.b, make([]float64, n), []float64{math.Inf(1), math.Inf(1), math.Inf(1), math.Inf(1)}[:n])
+		c.Check(x.EqualsApprox(NewDense(n, 1, want), 1e-12), check.Equals, true, check.Commentf("Test %d: got %v want %v", i, x, want))
+		for _, j := range active {
+			c.Check(x.At(j, 0), check.Equals, 0.)
+		}
+		if test.active != nil {
+			c.Check(active, check.DeepEquals, test.active, check.Commentf("Test %d", i))
+		}
+	}
+}
+
+func (s *S) TestBoundedLSQ(c *check.C) {
+	inf := math.Inf(1)
+	for i, test := range []struct {
+		a      *Dense
+		b      []float64
+		lo, hi []float64
+	}{
+		{
+			a:  newIdentity(4),
+			b:  []float64{-3, 0.5, 7, 2},
+			lo: []float64{-1, 0, 0, -inf},
+			hi: []float64{1, 1, 5, inf},
+		},
+		{
+			a: NewDense(5, 3, []float64{
+				1, 2, 0.5,
+				0.3, 1, 2,
+				2, 0.1, 1,
+				1, 1, 1,
+				0.5, 3, 0.2,
+			}),
+			b:  []float64{3, -1, 2, 1, 4},
+			lo: []float64{-inf, 0.5, -1},
+			hi: []float64{0.8, inf, 0},
+		},
+		{
+			a: NewDense(6, 4, []float64{
+				4, 1, 0, -1,
+				1, 3, 1, 0,
+				0, 1, 5, 2,
+				-1, 0, 2, 6,
+				1, 1, 1, 1,
+				2, -1, 3, 0,
+			}),
+			b:  []float64{1, -5, 10, 3, 0, -2},
+			lo: []float64{-0.5, -0.5, -0.5, -0.5},
+			hi: []float64{0.5, 0.5, 0.5, 0.5},
+		},
+		{
+			a: NewDense(3, 2, []float64{
+				1, 1,
+				1, 2,
+				1, 3,
+			}),
+			b:  []float64{1, 2, 2},
+			lo: []float64{1, -inf},
+			hi: []float64{1, inf},
+		},
+	} {
+		m, n := test.a.Dims()
+		x, lower, upper, err := BoundedLSQ(test.a, NewDense(m, 1, test.b), test.lo, test.hi)
+		c.Assert(err, check.Equals, nil, check.Commentf("Test %d", i))
+		want := bruteBoundedLSQ(test.a, test.b, test.lo, test.hi)
+		c.Check(x.EqualsApprox(NewDense(n, 1, want), 1e-12), check.Equals, true, check.Commentf("Test %d: got %v want %v", i, x, want))
+		for _, j := range lower {
+			c.Check(x.At(j, 0), check.Equals, test.lo[j])
+		}
+		for _, j := range upper {
+			c.Check(x.At(j, 0), check.Equals, test.hi[j])
+		}
+	}
+
+	c.Check(func() {
+		BoundedLSQ(newIdentity(2), NewDense(2, 1, nil), []float64{0, 1}, []float64{1, 0})
+	}, check.PanicMatches, string(ErrBounds))
+}
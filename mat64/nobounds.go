@@ -0,0 +1,12 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build nobounds
+// +build nobounds
+
+package mat64
+
+// checkBounds is false when built with the nobounds tag, so element accessors
+// do not check their indices.
+const checkBounds = false
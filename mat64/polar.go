@@ -0,0 +1,190 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+)
+
+// PolarFactors holds a polar decomposition a = u*h.
+type PolarFactors struct {
+	U, H *Dense
+}
+
+// Polar computes the polar decomposition of an m-by-n matrix a, a = u*h, from
+// the singular value decomposition a = w*s*v' as u = w*v' and h = v*s*v'. The
+// n-by-n matrix h is symmetric positive semidefinite. If m >= n the columns of
+// u are orthonormal and u is the matrix with orthonormal columns nearest to a
+// in the Frobenius norm; otherwise the rows of u are orthonormal. The matrix a
+// is not altered.
+func Polar(a Matrix) PolarFactors {
+	svd := SVDJobs(DenseCopyOf(a), epsilon, small, SVDThin, SVDThin, false)
+
+	var vt Dense
+	vt.TCopy(svd.V)
+	u := &Dense{}
+	u.Mul(svd.U, &vt)
+
+	vs := DenseCopyOf(svd.V)
+	n, _ := vs.Dims()
+	for j, s := range svd.Sigma {
+		for i := 0; i < n; i++ {
+			vs.Set(i, j, vs.At(i, j)*s)
+		}
+	}
+	h := &Dense{}
+	h.Mul(vs, &vt)
+	symmetrize(h)
+
+	return PolarFactors{U: u, H: h}
+}
+
+// PolarIter computes the polar decomposition of an m-by-n matrix a of full
+// column rank, with m >= n, by iteration on a scaled copy of a. Halley's
+// iteration, x = x*(3*I + x'*x)*inv(I + 3*x'*x), is used until x'*x is close
+// to the identity and the Newton-Schulz iteration, x = x*(3*I - x'*x)/2, which
+// needs no inversion, completes the convergence. The iteration stops when the
+// relative change in x is no greater than epsilon. The matrix a is not altered.
+//
+// PolarIter returns ErrNoConvergence if x has not converged after maxIter
+// iterations. Each Halley step at most triples the smallest singular value of
+// x, so a nearly rank deficient a needs many iterations and a rank deficient a
+// never converges; Polar should be used for such matrices.
+func PolarIter(a Matrix, epsilon float64, maxIter int) (PolarFactors, error) {
+	m, n := a.Dims()
+	if m < n {
+		panic(opError(ErrShape, "PolarIter", a))
+	}
+
+	x := DenseCopyOf(a)
+	if norm := x.Norm(0); norm != 0 {
+		x.Scale(1/norm, x)
+	}
+
+	eye := newIdentity(n)
+	var xt, xtx, step, next Dense
+	var final bool
+	for iter := 0; ; iter++ {
+		if iter == maxIter {
+			return PolarFactors{}, ErrNoConvergence
+		}
+		xt.TCopy(x)
+		xtx.Mul(&xt, x)
+
+		step.Sub(&xtx, eye)
+		halley := step.Norm(0) > 0.5
+		if halley {
+			// Halley step. The numerator and denominator commute
+			// so the inverse may be applied on the left.
+			num := &Dense{}
+			num.Scale(3, eye)
+			num.Add(num, &xtx)
+			den := &Dense{}
+			den.Scale(3, &xtx)
+			den.Add(den, eye)
+			step.Clone(LU(den).Solve(num))
+		} else {
+			// Newton-Schulz step.
+			step.Scale(-0.5, &xtx)
+			for i := 0; i < n; i++ {
+				step.Set(i, i, step.At(i, i)+1.5)
+			}
+		}
+		next.Mul(x, &step)
+
+		var diff Dense
+		diff.Sub(&next, x)
+		change := diff.Norm(0) / next.Norm(0)
+		x.Clone(&next)
+		if halley {
+			// Small singular values grow slowly under Halley's
+			// iteration, so the change in x is not a reliable
+			// test of convergence until x'*x is near I.
+			continue
+		}
+		if final || change <= epsilon {
+			break
+		}
+		// Convergence is at least quadratic, so one further step
+		// brings a change below the square root of epsilon to
+		// within epsilon.
+		final = change <= math.Sqrt(epsilon)
+	}
+
+	xt.TCopy(x)
+	h := &Dense{}
+	h.Mul(&xt, a)
+	symmetrize(h)
+
+	return PolarFactors{U: x, H: h}, nil
+}
+
+// symmetrize replaces the square matrix a with (a+a')/2.
+func symmetrize(a *Dense) {
+	n, _ := a.Dims()
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			v := (a.At(i, j) + a.At(j, i)) / 2
+			a.Set(i, j, v)
+			a.Set(j, i, v)
+		}
+	}
+}
+
+// Procrustes returns the orthogonal n-by-n matrix r and the scale s that
+// minimize the Frobenius norm of s*a*r - b for m-by-n matrices a and b, whose
+// rows usually hold corresponding points of two point sets already centered
+// on their centroids. If rotation is true, r is constrained to be a proper
+// rotation with determinant +1. If scale is false, s is fixed at one.
+//
+// With a'*b = u*sigma*v', r = u*d*v' where d is the identity or, if a rotation
+// is required and u*v' is a reflection, the identity with its last element
+// negated; s = trace(sigma*d)/trace(a'*a).
+func Procrustes(a, b Matrix, rotation, scale bool) (r *Dense, s float64) {
+	am, an := a.Dims()
+	bm, bn := b.Dims()
+	if am != bm || an != bn {
+		panic(opError(ErrShape, "Procrustes", a, b))
+	}
+
+	var at, atb Dense
+	at.TCopy(a)
+	atb.Mul(&at, b)
+	svd := SVDJobs(&atb, epsilon, small, SVDThin, SVDThin, false)
+
+	d := make([]float64, an)
+	for i := range d {
+		d[i] = 1
+	}
+	if rotation {
+		var uvt Dense
+		uvt.TCopy(svd.V)
+		uvt.Mul(svd.U, &uvt)
+		if LU(&uvt).Det() < 0 {
+			d[an-1] = -1
+		}
+	}
+
+	ud := DenseCopyOf(svd.U)
+	for j, v := range d {
+		for i := 0; i < an; i++ {
+			ud.Set(i, j, ud.At(i, j)*v)
+		}
+	}
+	var vt Dense
+	vt.TCopy(svd.V)
+	r = &Dense{}
+	r.Mul(ud, &vt)
+
+	s = 1
+	if scale {
+		var tr float64
+		for i, v := range svd.Sigma {
+			tr += v * d[i]
+		}
+		s = tr / at.Dot(&at)
+	}
+	return r, s
+}
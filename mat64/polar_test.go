@@ -0,0 +1,108 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+)
+
+// rotation3 returns the rotation through theta about the z axis followed by
+// the rotation through phi about the x axis.
+func rotation3(theta, phi float64) *Dense {
+	st, ct := math.Sincos(theta)
+	sp, cp := math.Sincos(phi)
+	rz := NewDense(3, 3, []float64{ct, -st, 0, st, ct, 0, 0, 0, 1})
+	rx := NewDense(3, 3, []float64{1, 0, 0, 0, cp, -sp, 0, sp, cp})
+	r := &Dense{}
+	r.Mul(rx, rz)
+	return r
+}
+
+func (s *S) TestPolar(c *check.C) {
+	for i, a := range []*Dense{
+		NewDense(3, 3, []float64{
+			4, 1, 2,
+			-1, 3, 0,
+			2, 0.5, 5,
+		}),
+		NewDense(4, 3, []float64{
+			1, 2, 0,
+			0, 1, 3,
+			2, -1, 1,
+			1, 1, 1,
+		}),
+		NewDense(3, 3, []float64{
+			1e-3, 0, 0,
+			0, 1, 2,
+			0, 0, 1e3,
+		}),
+	} {
+		_, n := a.Dims()
+		want := Polar(a)
+
+		var utu Dense
+		utu.TCopy(want.U)
+		utu.Mul(&utu, want.U)
+		c.Check(utu.EqualsApprox(newIdentity(n), 1e-14), check.Equals, true, check.Commentf("Test %d", i))
+		c.Check(symmetric(want.H), check.Equals, true, check.Commentf("Test %d", i))
+		for _, ev := range Eigen(DenseCopyOf(want.H), epsilon).d {
+			c.Check(ev >= 0, check.Equals, true, check.Commentf("Test %d: eigenvalue %v", i, ev))
+		}
+		var uh Dense
+		uh.Mul(want.U, want.H)
+		c.Check(uh.EqualsApprox(a, 1e-12*a.Norm(0)), check.Equals, true, check.Commentf("Test %d", i))
+
+		got, err := PolarIter(a, 1e-15, 100)
+		c.Assert(err, check.Equals, nil, check.Commentf("Test %d", i))
+		c.Check(got.U.EqualsApprox(want.U, 1e-12), check.Equals, true, check.Commentf("Test %d", i))
+		c.Check(got.H.EqualsApprox(want.H, 1e-12*a.Norm(0)), check.Equals, true, check.Commentf("Test %d", i))
+	}
+
+	_, err := PolarIter(NewDense(2, 2, []float64{1, 1, 1, 1}), 1e-15, 50)
+	c.Check(err, check.Equals, ErrNoConvergence)
+	_, err = PolarIter(NewDense(2, 2, []float64{1, 0, 0, 1e-12}), 1e-15, 5)
+	c.Check(err, check.Equals, ErrNoConvergence)
+}
+
+func (s *S) TestProcrustes(c *check.C) {
+	points := NewDense(5, 3, []float64{
+		1, 0, 0,
+		0, 2, 0,
+		0, 0, 3,
+		-1, -2, 0.5,
+		0, 0, -3.5,
+	})
+	reflection := NewDense(3, 3, []float64{1, 0, 0, 0, 1, 0, 0, 0, -1})
+	for i, test := range []struct {
+		r        *Dense
+		s        float64
+		rotation bool
+		scale    bool
+	}{
+		{r: rotation3(0.3, 1.2), s: 1},
+		{r: rotation3(-2, 0.4), s: 2.5, scale: true},
+		{r: rotation3(1, 1), s: 0.5, rotation: true, scale: true},
+		{r: reflection, s: 1},
+	} {
+		var b Dense
+		b.Mul(points, test.r)
+		b.Scale(test.s, &b)
+
+		r, s := Procrustes(points, &b, test.rotation, test.scale)
+		c.Check(r.EqualsApprox(test.r, 1e-14), check.Equals, true, check.Commentf("Test %d", i))
+		c.Check(math.Abs(s-test.s) < 1e-14, check.Equals, true, check.Commentf("Test %d: scale %v", i, s))
+	}
+
+	// A reflected point set is matched by the nearest proper rotation.
+	var b Dense
+	b.Mul(points, reflection)
+	r, _ := Procrustes(points, &b, true, false)
+	c.Check(math.Abs(LU(DenseCopyOf(r)).Det()-1) < 1e-14, check.Equals, true)
+	var rtr Dense
+	rtr.TCopy(r)
+	rtr.Mul(&rtr, r)
+	c.Check(rtr.EqualsApprox(newIdentity(3), 1e-14), check.Equals, true)
+}
@@ -27,7 +27,7 @@ func QR(a *Dense) QRFactor {
 	// Initialize.
 	m, n := a.Dims()
 	if m < n {
-		panic(ErrShape)
+		panic(opError(ErrShape, "QR", a))
 	}
 
 	qr := a
@@ -146,10 +146,10 @@ func (f QRFactor) Solve(b *Dense) (x *Dense) {
 	m, n := qr.Dims()
 	bm, bn := b.Dims()
 	if bm != m {
-		panic(ErrShape)
+		panic(opError(ErrShape, "QRFactor.Solve", qr, b))
 	}
 	if !f.IsFullRank() {
-		panic("mat64: matrix is rank deficient")
+		panic(opError(ErrRankDeficient, "QRFactor.Solve", qr, b))
 	}
 
 	// Compute Y = transpose(Q)*B
@@ -186,3 +186,128 @@ func (f QRFactor) Solve(b *Dense) (x *Dense) {
 
 	return x
 }
+
+// SolveTrans computes the minimum norm solution of a'.x = b where b has as many
+// rows as a has columns, using the factors of a rather than factorizing its
+// transpose. With a = Q*R, x = Q*inv(R')*b. SolveTrans will panic if a is not
+// full rank. The matrix b is not altered.
+func (f QRFactor) SolveTrans(b *Dense) (x *Dense) {
+	qr := f.QR
+	m, n := qr.Dims()
+	bm, bn := b.Dims()
+	if bm != n {
+		panic(opError(ErrShape, "QRFactor.SolveTrans", qr, b))
+	}
+	if !f.IsFullRank() {
+		panic(opError(ErrRankDeficient, "QRFactor.SolveTrans", qr, b))
+	}
+
+	x = NewDense(m, bn, nil)
+	y := make([]float64, m)
+	for j := 0; j < bn; j++ {
+		for i := range y {
+			y[i] = 0
+		}
+		for i := 0; i < n; i++ {
+			y[i] = b.At(i, j)
+		}
+
+		// Solve R'*Z = B and compute X = Q*Z.
+		f.solveRTransVec(y[:n])
+		for k := n - 1; k >= 0; k-- {
+			var s float64
+			for i := k; i < m; i++ {
+				s += qr.At(i, k) * y[i]
+			}
+			s /= -qr.At(k, k)
+			for i := k; i < m; i++ {
+				y[i] += s * qr.At(i, k)
+			}
+		}
+
+		for i, v := range y {
+			x.Set(i, j, v)
+		}
+	}
+	return x
+}
+
+// RCond returns an estimate of the reciprocal of the 1-norm condition number of
+// the triangular factor r, 1/(norm(r, 1)*norm(inv(r), 1)), computed as for
+// LUFactors.RCond. Since a and r share their singular values, this reflects the
+// conditioning of the least squares problem for a. RCond returns zero if a does
+// not have full rank.
+func (f QRFactor) RCond() float64 {
+	if !f.IsFullRank() {
+		return 0
+	}
+	n := len(f.rDiag)
+	r := f.R()
+	return 1 / (norm1(r) * normEst1(n, f.solveRVec, f.solveRTransVec))
+}
+
+// SolveRefine returns the solution x of a.x = b for the square matrix a = q.r,
+// refined and reported on as for LUFactors.SolveRefine, with the reciprocal
+// condition number that of r. SolveRefine will panic with ErrSquare if a is not
+// square. The matrices a and b are not altered.
+func (f QRFactor) SolveRefine(a, b *Dense) (x *Dense, info SolveInfo, err error) {
+	m, n := f.QR.Dims()
+	if m != n {
+		panic(opError(ErrSquare, "QRFactor.SolveRefine", f.QR))
+	}
+	return solveRefine(f, f.RCond(), a, b)
+}
+
+// solveVec overwrites x with the solution of a*y = x for square a.
+func (f QRFactor) solveVec(x []float64) {
+	qr := f.QR
+	n := len(x)
+	for k := 0; k < n; k++ {
+		var s float64
+		for i := k; i < n; i++ {
+			s += qr.At(i, k) * x[i]
+		}
+		s /= -qr.At(k, k)
+		for i := k; i < n; i++ {
+			x[i] += s * qr.At(i, k)
+		}
+	}
+	f.solveRVec(x)
+}
+
+// solveTransVec overwrites x with the solution of a'*y = x for square a.
+func (f QRFactor) solveTransVec(x []float64) {
+	qr := f.QR
+	n := len(x)
+	f.solveRTransVec(x)
+	for k := n - 1; k >= 0; k-- {
+		var s float64
+		for i := k; i < n; i++ {
+			s += qr.At(i, k) * x[i]
+		}
+		s /= -qr.At(k, k)
+		for i := k; i < n; i++ {
+			x[i] += s * qr.At(i, k)
+		}
+	}
+}
+
+// solveRVec overwrites x with the solution of r*y = x.
+func (f QRFactor) solveRVec(x []float64) {
+	for k := len(x) - 1; k >= 0; k-- {
+		for j := k + 1; j < len(x); j++ {
+			x[k] -= f.QR.At(k, j) * x[j]
+		}
+		x[k] /= f.rDiag[k]
+	}
+}
+
+// solveRTransVec overwrites x with the solution of r'*y = x.
+func (f QRFactor) solveRTransVec(x []float64) {
+	for k := range x {
+		for j := 0; j < k; j++ {
+			x[k] -= f.QR.At(j, k) * x[j]
+		}
+		x[k] /= f.rDiag[k]
+	}
+}
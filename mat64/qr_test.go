@@ -78,3 +78,29 @@ func (s *S) TestQRD(c *check.C) {
 		c.Check(a.EqualsApprox(newA, 1e-13), check.Equals, true, check.Commentf("Test %v: Q*R != A", test.name))
 	}
 }
+
+func (s *S) TestQRRCond(c *check.C) {
+	for i, a := range []*Dense{
+		NewDense(3, 2, []float64{
+			1, 2,
+			3, 4,
+			5, 6.5,
+		}),
+		NewDense(5, 3, []float64{
+			1, 1e-3, 0,
+			0, 1, 2,
+			4, 0, 1e2,
+			1, 1, 1,
+			0, 2, -1,
+		}),
+		hilbert(7),
+	} {
+		f := QR(DenseCopyOf(a))
+		want := rcond(f.R())
+		got := f.RCond()
+		c.Check(got >= want*(1-1e-6) && got <= 3*want, check.Equals, true,
+			check.Commentf("Test %d: got %v want %v", i, got, want))
+	}
+
+	c.Check(QR(NewDense(3, 2, []float64{1, 2, 2, 4, 3, 6})).RCond() < epsilon, check.Equals, true)
+}
@@ -0,0 +1,80 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math/rand"
+)
+
+// RandomizedSVD computes an approximation to the k leading singular triplets of
+// an m-by-n matrix a using the randomized range finder of Halko, Martinsson and
+// Tropp. The range of a is sampled with k+oversample Gaussian test vectors drawn
+// from rnd, refined by power subspace iterations, and the singular value
+// decomposition of a projected onto that range is computed with SVD.
+//
+// The returned factors have an m-by-k matrix u, k singular values in decreasing
+// order and an n-by-k matrix v so that u*s*v' approximates a. Only products with
+// a and its transpose are formed and a is not altered. The accuracy of the
+// approximation improves with oversample and, for slowly decaying singular values,
+// with power. Results are reproducible for a given state of rnd. If rnd is nil,
+// the default source of math/rand is used.
+//
+// RandomizedSVD will panic with ErrShape if k is not positive or exceeds min(m, n).
+func RandomizedSVD(a Matrix, k, oversample, power int, rnd *rand.Rand, epsilon, small float64) SVDFactors {
+	m, n := a.Dims()
+	if k <= 0 || k > min(m, n) {
+		panic(opError(ErrShape, "RandomizedSVD", a))
+	}
+	l := min(k+max(oversample, 0), min(m, n))
+
+	norm := rand.NormFloat64
+	if rnd != nil {
+		norm = rnd.NormFloat64
+	}
+	omega := NewDense(n, l, nil)
+	for i := range omega.mat.Data {
+		omega.mat.Data[i] = norm()
+	}
+
+	// Find an orthonormal basis q for the range of a*omega, re-orthonormalising
+	// between applications of a and a' to retain the small singular directions.
+	y := &Dense{}
+	y.Mul(a, omega)
+	q := QR(y).Q()
+	for i := 0; i < power; i++ {
+		z := &Dense{}
+		z.TCopy(project(q, a))
+		y.Mul(a, QR(z).Q())
+		q = QR(y).Q()
+	}
+
+	// Compute the SVD of b = q'*a, a small l-by-n matrix.
+	b := project(q, a)
+	svd := SVDJobs(b, epsilon, small, SVDThin, SVDThin, false)
+
+	u := &Dense{}
+	u.Mul(q, svd.U)
+	var uk, vk Dense
+	uk.View(u, 0, 0, m, k)
+	vk.View(svd.V, 0, 0, n, k)
+
+	return SVDFactors{
+		U:     &uk,
+		Sigma: svd.Sigma[:k],
+		V:     &vk,
+
+		m: max(m, n), n: k,
+	}
+}
+
+// project returns the l-by-n product q'*a for an m-by-n matrix a and an
+// m-by-l matrix q.
+func project(q *Dense, a Matrix) *Dense {
+	qt := &Dense{}
+	qt.TCopy(q)
+	b := &Dense{}
+	b.Mul(qt, a)
+	return b
+}
@@ -0,0 +1,65 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+	"math/rand"
+)
+
+func (s *S) TestRandomizedSVD(c *check.C) {
+	// a = u*diag(sigma)*v' with u and v exactly orthogonal.
+	sigma := []float64{100, 50, 20, 10, 1e-3, 1e-4, 1e-5, 1e-6}
+	h := hadamard(16)
+	var u, v Dense
+	u.View(h, 0, 0, 16, len(sigma))
+	v.View(h, 0, 8, 16, len(sigma))
+	us := &Dense{}
+	us.Clone(&u)
+	for j, s := range sigma {
+		for i := 0; i < 16; i++ {
+			us.Set(i, j, us.At(i, j)*s)
+		}
+	}
+	var vt Dense
+	vt.TCopy(&v)
+	a := &Dense{}
+	a.Mul(us, &vt)
+	orig := DenseCopyOf(a)
+
+	for i, test := range []struct {
+		k, oversample, power int
+		tol                  float64
+	}{
+		{k: 4, oversample: 0, power: 0, tol: 1e-6},
+		{k: 4, oversample: 4, power: 2, tol: 1e-12},
+		{k: 2, oversample: 6, power: 1, tol: 1e-12},
+	} {
+		svd := RandomizedSVD(a, test.k, test.oversample, test.power, rand.New(rand.NewSource(1)), math.Pow(2, -52.0), math.Pow(2, -966.0))
+		c.Check(a.Equals(orig), check.Equals, true, check.Commentf("Test %d: input altered", i))
+		c.Check(len(svd.Sigma), check.Equals, test.k)
+		c.Check(maxRelErr(svd.Sigma, sigma[:test.k]) < test.tol, check.Equals, true,
+			check.Commentf("Test %d: got %v want %v", i, svd.Sigma, sigma[:test.k]))
+
+		ur, uc := svd.U.Dims()
+		vr, vc := svd.V.Dims()
+		c.Check([]int{ur, uc, vr, vc}, check.DeepEquals, []int{16, test.k, 16, test.k}, check.Commentf("Test %d", i))
+		c.Check(isOrthogonal(svd.U), check.Equals, true, check.Commentf("Test %d: U not orthogonal", i))
+		c.Check(isOrthogonal(svd.V), check.Equals, true, check.Commentf("Test %d: V not orthogonal", i))
+
+		// The truncated factors reconstruct the leading part of a.
+		var r, svt Dense
+		svt.TCopy(svd.V)
+		r.Mul(svd.U, svd.S())
+		r.Mul(&r, &svt)
+		r.Sub(&r, a)
+		c.Check(r.Norm(0) < 10*sigma[test.k], check.Equals, true, check.Commentf("Test %d: residual %v", i, r.Norm(0)))
+
+		again := RandomizedSVD(a, test.k, test.oversample, test.power, rand.New(rand.NewSource(1)), math.Pow(2, -52.0), math.Pow(2, -966.0))
+		c.Check(again.Sigma, check.DeepEquals, svd.Sigma, check.Commentf("Test %d: not reproducible", i))
+		c.Check(again.U.Equals(svd.U), check.Equals, true, check.Commentf("Test %d: not reproducible", i))
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+)
+
+// maxRefine is the maximum number of iterative refinement steps taken for
+// each column of a solution.
+const maxRefine = 5
+
+// SolveInfo reports the accuracy of a solution x of a*x = b computed with
+// iterative refinement. The slices hold one value for each column of b.
+type SolveInfo struct {
+	// RCond is an estimate of the reciprocal 1-norm condition number of a.
+	RCond float64
+
+	// Berr is the componentwise relative backward error, the smallest
+	// relative change in any element of a and b that makes x an exact
+	// solution.
+	Berr []float64
+
+	// NormBerr is the normwise backward error,
+	// norm(b-a*x, inf)/(norm(a, inf)*norm(x, inf) + norm(b, inf)).
+	NormBerr []float64
+
+	// Ferr is an estimated bound on the relative forward error
+	// norm(x-xtrue, inf)/norm(x, inf).
+	Ferr []float64
+
+	// Iter is the number of refinement steps taken.
+	Iter []int
+}
+
+// vecSolver is a factorization of a square matrix a that can solve
+// a*y = x and a'*y = x for a vector x in place.
+type vecSolver interface {
+	solveVec(x []float64)
+	solveTransVec(x []float64)
+}
+
+// SolveRefine returns the solution x of a*x = b for a square matrix a together
+// with a report of its accuracy. If a is symmetric and positive definite its
+// Cholesky factorization is used, otherwise its LU factorization. The solution
+// is improved by iterative refinement as described for LUFactors.SolveRefine.
+// The matrices a and b are not altered.
+//
+// SolveRefine returns ErrSingular and a nil x if a is exactly singular, and
+// ErrIllConditioned along with x if the estimated reciprocal condition number
+// is less than epsilon, in which case x may be inaccurate.
+func SolveRefine(a, b Matrix) (x *Dense, info SolveInfo, err error) {
+	m, n := a.Dims()
+	if m != n {
+		panic(opError(ErrSquare, "SolveRefine", a, b))
+	}
+	ad, bd := DenseCopyOf(a), DenseCopyOf(b)
+	if symmetric(ad) {
+		if f := Cholesky(ad); f.SPD {
+			return f.SolveRefine(ad, bd)
+		}
+	}
+	return LU(DenseCopyOf(ad)).SolveRefine(ad, bd)
+}
+
+// solveRefine solves a*x = b with the factorization f of a and refines each
+// column of x while its componentwise backward error is above epsilon and is
+// at least halved by each step, as in LAPACK's dgerfs.
+func solveRefine(f vecSolver, rcond float64, a, b *Dense) (*Dense, SolveInfo, error) {
+	n, _ := a.Dims()
+	bm, nrhs := b.Dims()
+	if bm != n {
+		panic(opError(ErrShape, "SolveRefine", a, b))
+	}
+	if rcond == 0 {
+		return nil, SolveInfo{}, ErrSingular
+	}
+
+	info := SolveInfo{
+		RCond:    rcond,
+		Berr:     make([]float64, nrhs),
+		NormBerr: make([]float64, nrhs),
+		Ferr:     make([]float64, nrhs),
+		Iter:     make([]int, nrhs),
+	}
+	anorm := normInf(a)
+	x := NewDense(n, nrhs, nil)
+	xj := make([]float64, n)
+	bj := make([]float64, n)
+	r := make([]float64, n)
+	w := make([]float64, n)
+	for j := 0; j < nrhs; j++ {
+		b.Col(bj, j)
+		copy(xj, bj)
+		f.solveVec(xj)
+
+		lstres := 3.
+		for iter := 0; ; iter++ {
+			// Form the residual r = b - a*x and the scale
+			// w = |b| + |a|*|x| of its components.
+			for i := range r {
+				r[i] = bj[i]
+				w[i] = math.Abs(bj[i])
+				for k, v := range a.rowView(i) {
+					r[i] -= v * xj[k]
+					w[i] += math.Abs(v) * math.Abs(xj[k])
+				}
+			}
+
+			var berr float64
+			for i, v := range r {
+				switch {
+				case w[i] != 0:
+					berr = math.Max(berr, math.Abs(v)/w[i])
+				case v != 0:
+					berr = math.Inf(1)
+				}
+			}
+			info.Berr[j] = berr
+			info.Iter[j] = iter
+			if berr <= epsilon || 2*berr > lstres || iter == maxRefine {
+				break
+			}
+
+			f.solveVec(r)
+			for i, v := range r {
+				xj[i] += v
+			}
+			lstres = berr
+		}
+
+		xnorm := vecNormInf(xj)
+		info.NormBerr[j] = vecNormInf(r) / (anorm*xnorm + vecNormInf(bj))
+
+		// Bound the forward error by
+		// norm(|inv(a)|*(|r| + (n+1)*eps*w), inf)/norm(x, inf), estimating
+		// norm(inv(a)*diag(w), inf) as the 1-norm of its transpose.
+		for i, v := range r {
+			w[i] = math.Abs(v) + float64(n+1)*epsilon*w[i]
+		}
+		mul := func(v []float64) {
+			f.solveTransVec(v)
+			for i := range v {
+				v[i] *= w[i]
+			}
+		}
+		mulTrans := func(v []float64) {
+			for i := range v {
+				v[i] *= w[i]
+			}
+			f.solveVec(v)
+		}
+		info.Ferr[j] = normEst1(n, mul, mulTrans)
+		if xnorm != 0 {
+			info.Ferr[j] /= xnorm
+		}
+
+		x.SetCol(j, xj)
+	}
+
+	if rcond < epsilon {
+		return x, info, ErrIllConditioned
+	}
+	return x, info, nil
+}
+
+// vecNormInf returns the infinity norm of x, the maximum absolute value of
+// its elements.
+func vecNormInf(x []float64) float64 {
+	var m float64
+	for _, v := range x {
+		m = math.Max(m, math.Abs(v))
+	}
+	return m
+}
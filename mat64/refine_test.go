@@ -0,0 +1,110 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+)
+
+func (s *S) TestSolveRefine(c *check.C) {
+	for i, test := range []struct {
+		a     *Dense
+		x     *Dense
+		solve func(a, b *Dense) (*Dense, SolveInfo, error)
+	}{
+		{
+			a: NewDense(3, 3, []float64{
+				4, -2, 1,
+				3, 6, -4,
+				2, 1, 8,
+			}),
+			x: NewDense(3, 2, []float64{1, -1, 2, 0.5, 3, 4}),
+		},
+		{
+			a: hilbert(8),
+			x: NewDense(8, 1, []float64{1, 1, 1, 1, 1, 1, 1, 1}),
+		},
+		{
+			a: NewDense(4, 4, []float64{
+				1e-8, 1, 0, 2,
+				1, 1, 3, 0,
+				0, 2, 1e4, 1,
+				3, 0, 1, 1,
+			}),
+			x: NewDense(4, 1, []float64{1, -2, 3e-4, 5}),
+			solve: func(a, b *Dense) (*Dense, SolveInfo, error) {
+				return LU(DenseCopyOf(a)).SolveRefine(a, b)
+			},
+		},
+		{
+			a: NewDense(3, 3, []float64{
+				2, 1, 1,
+				1, 3, -1,
+				0, 1, 4,
+			}),
+			x: NewDense(3, 1, []float64{1, 2, 3}),
+			solve: func(a, b *Dense) (*Dense, SolveInfo, error) {
+				return QR(DenseCopyOf(a)).SolveRefine(a, b)
+			},
+		},
+		{
+			a: hilbert(6),
+			x: NewDense(6, 1, []float64{1, -1, 1, -1, 1, -1}),
+			solve: func(a, b *Dense) (*Dense, SolveInfo, error) {
+				return Cholesky(DenseCopyOf(a)).SolveRefine(a, b)
+			},
+		},
+	} {
+		var b Dense
+		b.Mul(test.a, test.x)
+		var (
+			x    *Dense
+			info SolveInfo
+			err  error
+		)
+		if test.solve == nil {
+			x, info, err = SolveRefine(test.a, &b)
+		} else {
+			x, info, err = test.solve(test.a, &b)
+		}
+		c.Assert(err, check.Equals, nil, check.Commentf("Test %d", i))
+
+		// The estimate for the QR factorization is that of r, whose
+		// 1-norm condition differs from that of a by at most a factor n.
+		want := rcond(test.a)
+		c.Check(info.RCond >= want/10 && info.RCond <= 10*want, check.Equals, true,
+			check.Commentf("Test %d: rcond %v want %v", i, info.RCond, want))
+
+		_, nrhs := x.Dims()
+		n, _ := x.Dims()
+		for j := 0; j < nrhs; j++ {
+			var errMax, xMax float64
+			for k := 0; k < n; k++ {
+				errMax = math.Max(errMax, math.Abs(x.At(k, j)-test.x.At(k, j)))
+				xMax = math.Max(xMax, math.Abs(x.At(k, j)))
+			}
+			c.Check(info.Berr[j] <= 2*epsilon, check.Equals, true,
+				check.Commentf("Test %d: berr %v", i, info.Berr[j]))
+			c.Check(info.NormBerr[j] <= info.Berr[j]+epsilon, check.Equals, true,
+				check.Commentf("Test %d: normwise berr %v", i, info.NormBerr[j]))
+			c.Check(errMax/xMax <= info.Ferr[j], check.Equals, true,
+				check.Commentf("Test %d: error %v bound %v", i, errMax/xMax, info.Ferr[j]))
+			c.Check(info.Ferr[j] <= 1e3*epsilon/info.RCond, check.Equals, true,
+				check.Commentf("Test %d: bound %v", i, info.Ferr[j]))
+		}
+	}
+
+	_, _, err := SolveRefine(NewDense(2, 2, []float64{1, 2, 2, 4}), NewDense(2, 1, []float64{1, 1}))
+	c.Check(err, check.Equals, ErrSingular)
+
+	h := hilbert(14)
+	var b Dense
+	b.Mul(h, NewDense(14, 1, []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}))
+	x, info, err := SolveRefine(h, &b)
+	c.Check(err, check.Equals, ErrIllConditioned)
+	c.Check(x, check.NotNil)
+	c.Check(info.RCond < epsilon, check.Equals, true)
+}
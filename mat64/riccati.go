@@ -0,0 +1,203 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+)
+
+// CARE returns the stabilizing solution x of the continuous algebraic Riccati
+// equation
+//
+//  a'*x + x*a - x*b*inv(r)*b'*x + q = 0
+//
+// for an n-by-n matrix a, an n-by-m matrix b, a symmetric n-by-n matrix q and
+// a symmetric positive definite m-by-m matrix r, together with the eigenvalues
+// of the closed loop matrix a - b*inv(r)*b'*x, which all have negative real
+// parts. The solution is found by the Schur method: the real Schur form of the
+// Hamiltonian matrix
+//
+//  [ a  -b*inv(r)*b' ]
+//  [ -q      -a'     ]
+//
+// is reordered to place its stable eigenvalues first, and with [u1; u2] the
+// leading n Schur vectors, x = u2*inv(u1).
+//
+// CARE returns ErrSingular if r is singular and ErrNoStabilizing if the
+// Hamiltonian matrix has eigenvalues on the imaginary axis or no stabilizing
+// solution exists, as when (a, b) is not stabilizable.
+func CARE(a, b, q, r Matrix) (x *Dense, poles []complex128, err error) {
+	n, g, err := riccatiSetup("CARE", a, b, q, r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h := NewDense(2*n, 2*n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			h.Set(i, j, a.At(i, j))
+			h.Set(i, n+j, -g.At(i, j))
+			h.Set(n+i, j, -q.At(i, j))
+			h.Set(n+i, n+j, -a.At(j, i))
+		}
+	}
+
+	tol := 100 * epsilon * norm1(h)
+	stable := func(re, im float64) bool { return re < -tol }
+	boundary := func(re, im float64) bool { return math.Abs(re) <= tol }
+	return riccatiSchur(h, n, stable, boundary)
+}
+
+// DARE returns the stabilizing solution x of the discrete algebraic Riccati
+// equation
+//
+//  a'*x*a - x - a'*x*b*inv(r + b'*x*b)*b'*x*a + q = 0
+//
+// for an n-by-n matrix a, an n-by-m matrix b, a symmetric n-by-n matrix q and a
+// symmetric positive definite m-by-m matrix r, together with the eigenvalues of
+// the closed loop matrix a - b*inv(r + b'*x*b)*b'*x*a, which all lie inside the
+// unit circle. The matrix a may be singular, as it is for pure delays and
+// deadbeat modes.
+//
+// The solution is found by the Schur method on the symplectic pencil m - lambda*l
+// with g = b*inv(r)*b' and
+//
+//  m = [ a  0 ]    l = [ I  g  ]
+//      [-q  I ],       [ 0  a' ].
+//
+// Rather than inverting l, which is singular with a, the pencil is mapped by
+// the Cayley transform mu = (lambda-1)/(lambda+1) to the matrix
+//
+//  h = inv(m + l)*(m - l),
+//
+// which has the same invariant subspaces and takes the inside of the unit
+// circle to the left half plane. m + l is singular only when -1 is an
+// eigenvalue of the pencil. The real Schur form of h is reordered to place its
+// eigenvalues with negative real part first, and with [u1; u2] the leading n
+// Schur vectors, x = u2*inv(u1).
+//
+// DARE returns ErrSingular if r is singular and ErrNoStabilizing if the pencil
+// has eigenvalues on the unit circle or no stabilizing solution exists.
+func DARE(a, b, q, r Matrix) (x *Dense, poles []complex128, err error) {
+	n, g, err := riccatiSetup("DARE", a, b, q, r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sum := NewDense(2*n, 2*n, nil)
+	diff := NewDense(2*n, 2*n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			aij, aji := a.At(i, j), a.At(j, i)
+			sum.Set(i, j, aij)
+			sum.Set(i, n+j, g.At(i, j))
+			sum.Set(n+i, j, -q.At(i, j))
+			sum.Set(n+i, n+j, aji)
+			diff.Set(i, j, aij)
+			diff.Set(i, n+j, -g.At(i, j))
+			diff.Set(n+i, j, -q.At(i, j))
+			diff.Set(n+i, n+j, -aji)
+		}
+		sum.Set(i, i, sum.At(i, i)+1)
+		sum.Set(n+i, n+i, sum.At(n+i, n+i)+1)
+		diff.Set(i, i, diff.At(i, i)-1)
+		diff.Set(n+i, n+i, diff.At(n+i, n+i)+1)
+	}
+
+	lu := LU(sum)
+	if lu.IsSingular() || lu.RCond() < epsilon {
+		return nil, nil, ErrNoStabilizing
+	}
+	h := lu.Solve(diff)
+
+	tol := 100 * epsilon * norm1(h)
+	stable := func(re, im float64) bool { return re < -tol }
+	boundary := func(re, im float64) bool { return math.Abs(re) <= tol }
+	x, poles, err = riccatiSchur(h, n, stable, boundary)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Map the eigenvalues back by lambda = (1+mu)/(1-mu).
+	for i, mu := range poles {
+		poles[i] = (1 + mu) / (1 - mu)
+	}
+	return x, poles, nil
+}
+
+// riccatiSetup checks the dimensions of the Riccati equation coefficients for
+// the operation op and returns the order of a and g = b*inv(r)*b'.
+func riccatiSetup(op string, a, b, q, r Matrix) (n int, g *Dense, err error) {
+	n, an := a.Dims()
+	bm, m := b.Dims()
+	qm, qn := q.Dims()
+	rm, rn := r.Dims()
+	if n != an || qm != qn || rm != rn {
+		panic(opError(ErrSquare, op, a, b, q, r))
+	}
+	if bm != n || qm != n || rm != m {
+		panic(opError(ErrShape, op, a, b, q, r))
+	}
+
+	lu := LU(DenseCopyOf(r))
+	if lu.IsSingular() {
+		return 0, nil, ErrSingular
+	}
+	var bt Dense
+	bt.TCopy(b)
+	g = &Dense{}
+	g.Mul(b, lu.Solve(&bt))
+	symmetrize(g)
+	return n, g, nil
+}
+
+// riccatiSchur returns x = u2*inv(u1) from the leading n Schur vectors [u1; u2]
+// of the 2n-by-2n matrix h after reordering the eigenvalues satisfying stable
+// first, together with those eigenvalues. It returns ErrNoStabilizing if any
+// eigenvalue satisfies boundary, the number of stable eigenvalues is not n or
+// u1 is singular.
+func riccatiSchur(h *Dense, n int, stable, boundary func(re, im float64) bool) (*Dense, []complex128, error) {
+	f := Schur(h, epsilon)
+	for i, re := range f.d {
+		if boundary(re, math.Abs(f.e[i])) {
+			return nil, nil, ErrNoStabilizing
+		}
+	}
+	k, err := reorderSchur(f.T, f.Z, stable)
+	if err != nil || k != n {
+		return nil, nil, ErrNoStabilizing
+	}
+
+	// The stable invariant subspace must be a graph over its
+	// first n coordinates. The singular values of u1 are at most
+	// one, so its smallest gives its distance from singularity.
+	u1, u2 := &Dense{}, &Dense{}
+	u1.Submatrix(f.Z, 0, 0, n, n)
+	u2.Submatrix(f.Z, n, 0, n, n)
+	sigma := SVDJobs(DenseCopyOf(u1), epsilon, small, SVDNone, SVDNone, false).Sigma
+	if sigma[n-1] <= 1e3*float64(n)*epsilon {
+		return nil, nil, ErrNoStabilizing
+	}
+
+	// Solve x*u1 = u2 as u1'*x' = u2'.
+	var u1t, u2t Dense
+	u1t.TCopy(u1)
+	u2t.TCopy(u2)
+	lu := LU(&u1t)
+	x := &Dense{}
+	x.TCopy(lu.Solve(&u2t))
+	symmetrize(x)
+
+	poles := make([]complex128, 0, n)
+	blk := schurBlocks(f.T)
+	for b := 0; blk[b] < n; b++ {
+		re, im := blockValue(f.T, blk[b], blk[b+1]-blk[b])
+		poles = append(poles, complex(re, im))
+		if im != 0 {
+			poles = append(poles, complex(re, -im))
+		}
+	}
+	return x, poles, nil
+}
@@ -0,0 +1,251 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// sortPoles sorts p by real and then imaginary part.
+func sortPoles(p []complex128) {
+	sort.Slice(p, func(i, j int) bool {
+		if real(p[i]) != real(p[j]) {
+			return real(p[i]) < real(p[j])
+		}
+		return imag(p[i]) < imag(p[j])
+	})
+}
+
+func checkPoles(c *check.C, got, want []complex128, comment check.CommentInterface) {
+	c.Assert(len(got), check.Equals, len(want), comment)
+	sortPoles(got)
+	sortPoles(want)
+	for i := range got {
+		c.Check(cmplx.Abs(got[i]-want[i]) < 1e-12, check.Equals, true, comment)
+	}
+}
+
+func (s *S) TestCARE(c *check.C) {
+	s3 := math.Sqrt(3)
+	for i, test := range []struct {
+		a, b, q, r *Dense
+		x          *Dense
+		poles      []complex128
+		err        error
+	}{
+		{
+			a:     NewDense(1, 1, []float64{1}),
+			b:     NewDense(1, 1, []float64{1}),
+			q:     NewDense(1, 1, []float64{1}),
+			r:     NewDense(1, 1, []float64{1}),
+			x:     NewDense(1, 1, []float64{1 + math.Sqrt2}),
+			poles: []complex128{-math.Sqrt2},
+		},
+		{
+			// LQR of the double integrator.
+			a:     NewDense(2, 2, []float64{0, 1, 0, 0}),
+			b:     NewDense(2, 1, []float64{0, 1}),
+			q:     NewDense(2, 2, []float64{1, 0, 0, 1}),
+			r:     NewDense(1, 1, []float64{1}),
+			x:     NewDense(2, 2, []float64{s3, 1, 1, s3}),
+			poles: []complex128{complex(-s3/2, 0.5), complex(-s3/2, -0.5)},
+		},
+		{
+			a: NewDense(4, 4, []float64{
+				0.5, 1, 0, 0.2,
+				-1, 0.3, 0.4, 0,
+				0, 0.2, -2, 1,
+				0.1, 0, 0.5, 1.5,
+			}),
+			b: NewDense(4, 2, []float64{
+				1, 0,
+				0, 0,
+				0, 1,
+				1, 1,
+			}),
+			q: NewDense(4, 4, []float64{
+				2, 0, 0, 0,
+				0, 1, 0, 0,
+				0, 0, 1, 0.5,
+				0, 0, 0.5, 3,
+			}),
+			r: NewDense(2, 2, []float64{1, 0.2, 0.2, 2}),
+		},
+		{
+			// The unstable mode is not controllable.
+			a:   NewDense(2, 2, []float64{1, 0, 0, -1}),
+			b:   NewDense(2, 1, []float64{0, 1}),
+			q:   NewDense(2, 2, []float64{1, 0, 0, 1}),
+			r:   NewDense(1, 1, []float64{1}),
+			err: ErrNoStabilizing,
+		},
+	} {
+		x, poles, err := CARE(test.a, test.b, test.q, test.r)
+		c.Check(err, check.Equals, test.err, check.Commentf("Test %d", i))
+		if test.err != nil {
+			continue
+		}
+		if test.x != nil {
+			c.Check(x.EqualsApprox(test.x, 1e-13), check.Equals, true, check.Commentf("Test %d", i))
+		}
+		if test.poles != nil {
+			checkPoles(c, poles, test.poles, check.Commentf("Test %d", i))
+		}
+
+		// Check the residual and the closed loop eigenvalues.
+		var at, bt, xa, res, k, acl Dense
+		at.TCopy(test.a)
+		bt.TCopy(test.b)
+		res.Mul(&at, x)
+		xa.Mul(x, test.a)
+		res.Add(&res, &xa)
+		k.Mul(&bt, x)
+		k.Clone(Solve(test.r, &k))
+		var xbk Dense
+		xbk.Mul(x, test.b)
+		xbk.Mul(&xbk, &k)
+		res.Sub(&res, &xbk)
+		res.Add(&res, test.q)
+		c.Check(res.Norm(0) <= 1e-12*test.q.Norm(0), check.Equals, true,
+			check.Commentf("Test %d: residual %v", i, res.Norm(0)))
+
+		acl.Mul(test.b, &k)
+		acl.Sub(test.a, &acl)
+		ef := Eigen(&acl, epsilon)
+		var want []complex128
+		for j, re := range ef.d {
+			want = append(want, complex(re, ef.e[j]))
+		}
+		checkPoles(c, poles, want, check.Commentf("Test %d", i))
+		for _, p := range poles {
+			c.Check(real(p) < 0, check.Equals, true, check.Commentf("Test %d: pole %v", i, p))
+		}
+	}
+}
+
+func (s *S) TestDARE(c *check.C) {
+	s5 := math.Sqrt(5)
+	for i, test := range []struct {
+		a, b, q, r *Dense
+		x          *Dense
+		poles      []complex128
+		err        error
+	}{
+		{
+			a:     NewDense(1, 1, []float64{2}),
+			b:     NewDense(1, 1, []float64{1}),
+			q:     NewDense(1, 1, []float64{1}),
+			r:     NewDense(1, 1, []float64{1}),
+			x:     NewDense(1, 1, []float64{2 + s5}),
+			poles: []complex128{complex((3-s5)/2, 0)},
+		},
+		{
+			a: NewDense(3, 3, []float64{
+				1.1, 0.5, 0,
+				-0.5, 0.9, 0.2,
+				0, 0.1, 0.7,
+			}),
+			b: NewDense(3, 1, []float64{0, 1, 0.5}),
+			q: NewDense(3, 3, []float64{
+				1, 0, 0,
+				0, 2, 0,
+				0, 0, 1,
+			}),
+			r: NewDense(1, 1, []float64{0.5}),
+		},
+		{
+			a:   NewDense(2, 2, []float64{2, 0, 0, 0.5}),
+			b:   NewDense(2, 1, []float64{0, 1}),
+			q:   NewDense(2, 2, []float64{1, 0, 0, 1}),
+			r:   NewDense(1, 1, []float64{1}),
+			err: ErrNoStabilizing,
+		},
+		{
+			// A singular a with an uncontrollable mode on the
+			// unit circle.
+			a:   NewDense(2, 2, []float64{1, 0, 0, 0}),
+			b:   NewDense(2, 1, []float64{0, 1}),
+			q:   NewDense(2, 2, []float64{1, 0, 0, 1}),
+			r:   NewDense(1, 1, []float64{1}),
+			err: ErrNoStabilizing,
+		},
+		{
+			// A pure delay, for which x = q.
+			a:     NewDense(1, 1, []float64{0}),
+			b:     NewDense(1, 1, []float64{1}),
+			q:     NewDense(1, 1, []float64{1}),
+			r:     NewDense(1, 1, []float64{1}),
+			x:     NewDense(1, 1, []float64{1}),
+			poles: []complex128{0},
+		},
+		{
+			// An input delay in front of a stable mode.
+			a: NewDense(2, 2, []float64{
+				0.5, 1,
+				0, 0,
+			}),
+			b: NewDense(2, 1, []float64{0, 1}),
+			q: NewDense(2, 2, []float64{
+				1, 0,
+				0, 1,
+			}),
+			r: NewDense(1, 1, []float64{1}),
+		},
+		{
+			a:   NewDense(2, 2, []float64{1, 0, 0, 1}),
+			b:   NewDense(2, 2, []float64{1, 0, 0, 1}),
+			q:   NewDense(2, 2, []float64{1, 0, 0, 1}),
+			r:   NewDense(2, 2, []float64{1, 1, 1, 1}),
+			err: ErrSingular,
+		},
+	} {
+		x, poles, err := DARE(test.a, test.b, test.q, test.r)
+		c.Check(err, check.Equals, test.err, check.Commentf("Test %d", i))
+		if test.err != nil {
+			continue
+		}
+		if test.x != nil {
+			c.Check(x.EqualsApprox(test.x, 1e-13), check.Equals, true, check.Commentf("Test %d", i))
+		}
+		if test.poles != nil {
+			checkPoles(c, poles, test.poles, check.Commentf("Test %d", i))
+		}
+
+		// Check the residual and the closed loop eigenvalues.
+		var at, bt, xa, xb, res, rbxb, k, acl Dense
+		at.TCopy(test.a)
+		bt.TCopy(test.b)
+		xa.Mul(x, test.a)
+		res.Mul(&at, &xa)
+		res.Sub(&res, x)
+		xb.Mul(x, test.b)
+		rbxb.Mul(&bt, &xb)
+		rbxb.Add(&rbxb, test.r)
+		k.Mul(&bt, &xa)
+		k.Clone(Solve(&rbxb, &k))
+		var axbk Dense
+		axbk.Mul(&at, &xb)
+		axbk.Mul(&axbk, &k)
+		res.Sub(&res, &axbk)
+		res.Add(&res, test.q)
+		c.Check(res.Norm(0) <= 1e-12*x.Norm(0), check.Equals, true,
+			check.Commentf("Test %d: residual %v", i, res.Norm(0)))
+
+		acl.Mul(test.b, &k)
+		acl.Sub(test.a, &acl)
+		ef := Eigen(&acl, epsilon)
+		var want []complex128
+		for j, re := range ef.d {
+			want = append(want, complex(re, ef.e[j]))
+		}
+		checkPoles(c, poles, want, check.Commentf("Test %d", i))
+		for _, p := range poles {
+			c.Check(cmplx.Abs(p) < 1, check.Equals, true, check.Commentf("Test %d: pole %v", i, p))
+		}
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+)
+
+// SchurFactors holds a real Schur decomposition a = z*t*z'.
+type SchurFactors struct {
+	T, Z *Dense
+	d, e []float64
+}
+
+// Schur computes the real Schur decomposition of the square matrix a, a = z*t*z',
+// where z is orthogonal and t is upper quasi-triangular. The 1-by-1 diagonal
+// blocks of t hold the real eigenvalues of a and the 2-by-2 diagonal blocks hold
+// complex conjugate pairs. The matrix a is overwritten by t during the
+// decomposition.
+func Schur(a *Dense, epsilon float64) SchurFactors {
+	m, n := a.Dims()
+	if m != n {
+		panic(opError(ErrSquare, "Schur", a))
+	}
+
+	d := make([]float64, n)
+	e := make([]float64, n)
+
+	// Reduce to Hessenberg form.
+	t, z := orthes(a)
+
+	// Reduce Hessenberg to real Schur form.
+	hqr(d, e, t, z, epsilon)
+
+	// Clear the Householder vectors left below the subdiagonal by orthes
+	// and the negligible subdiagonal elements between converged blocks.
+	for i := 1; i < n; i++ {
+		for j := 0; j < i-1; j++ {
+			t.Set(i, j, 0)
+		}
+		if e[i-1] <= 0 {
+			t.Set(i, i-1, 0)
+		}
+	}
+
+	return SchurFactors{T: t, Z: z, d: d, e: e}
+}
+
+// schurBlocks returns the starting indices of the diagonal blocks of the upper
+// quasi-triangular matrix t, followed by the order of t.
+func schurBlocks(t *Dense) []int {
+	n, _ := t.Dims()
+	var b []int
+	for i := 0; i < n; i++ {
+		b = append(b, i)
+		if i+1 < n && t.At(i+1, i) != 0 {
+			i++
+		}
+	}
+	return append(b, n)
+}
+
+// blockEigen returns the real part and the positive imaginary part of the
+// eigenvalues of the 2-by-2 diagonal block of t starting at i.
+func blockEigen(t *Dense, i int) (re, im float64) {
+	p := (t.At(i, i) - t.At(i+1, i+1)) / 2
+	re = (t.At(i, i) + t.At(i+1, i+1)) / 2
+	im = math.Sqrt(math.Abs(p*p + t.At(i, i+1)*t.At(i+1, i)))
+	return re, im
+}
+
+// sylvesterBlock returns the solution x of a*x + x*b = c for the small square
+// matrices a and b by solving the equivalent Kronecker product system.
+func sylvesterBlock(a, b, c *Dense) (*Dense, error) {
+	p, _ := a.Dims()
+	q, _ := b.Dims()
+	k := NewDense(p*q, p*q, nil)
+	rhs := NewDense(p*q, 1, nil)
+	for i := 0; i < p; i++ {
+		for j := 0; j < q; j++ {
+			row := i*q + j
+			for l := 0; l < p; l++ {
+				k.Set(row, l*q+j, k.At(row, l*q+j)+a.At(i, l))
+			}
+			for l := 0; l < q; l++ {
+				k.Set(row, i*q+l, k.At(row, i*q+l)+b.At(l, j))
+			}
+			rhs.Set(row, 0, c.At(i, j))
+		}
+	}
+	lu := LU(k)
+	if lu.IsSingular() {
+		return nil, ErrSingular
+	}
+	y := lu.Solve(rhs)
+	x := NewDense(p, q, nil)
+	for i := 0; i < p; i++ {
+		for j := 0; j < q; j++ {
+			x.Set(i, j, y.At(i*q+j, 0))
+		}
+	}
+	return x, nil
+}
+
+// similarity returns z*t*z'.
+func similarity(z, t *Dense) *Dense {
+	var zt Dense
+	zt.TCopy(z)
+	r := &Dense{}
+	r.Mul(z, t)
+	r.Mul(r, &zt)
+	return r
+}
+
+// reorderSchur reorders the real Schur decomposition with quasi-triangular t
+// and orthogonal z so that the diagonal blocks whose eigenvalues satisfy sel,
+// called with the real part and the non-negative imaginary part, lead t.
+// Selected blocks are moved up by swapping adjacent blocks. It returns the
+// order of the leading selected part of t.
+func reorderSchur(t, z *Dense, sel func(re, im float64) bool) (int, error) {
+	var pos int
+	for {
+		blk := schurBlocks(t)
+		next := -1
+		for b := 0; b < len(blk)-1; b++ {
+			if blk[b] < pos {
+				continue
+			}
+			if sel(blockValue(t, blk[b], blk[b+1]-blk[b])) {
+				next = b
+				break
+			}
+		}
+		if next < 0 {
+			return pos, nil
+		}
+
+		// Bubble the selected block up to pos.
+		size := blk[next+1] - blk[next]
+		for b := next; blk[b] > pos; b-- {
+			j, p, q := blk[b-1], blk[b]-blk[b-1], blk[b+1]-blk[b]
+			err := swapSchurBlocks(t, z, j, p, q)
+			if err != nil {
+				return pos, err
+			}
+			blk[b] = j + q
+		}
+		pos += size
+	}
+}
+
+// blockValue returns the eigenvalue with non-negative imaginary part of the
+// diagonal block of order p of t starting at i.
+func blockValue(t *Dense, i, p int) (re, im float64) {
+	if p == 1 {
+		return t.At(i, i), 0
+	}
+	return blockEigen(t, i)
+}
+
+// swapSchurBlocks exchanges the adjacent diagonal blocks of orders p and q of
+// the quasi-triangular t that start at j, updating z so that z*t*z' is
+// unchanged. The columns of [-x; I], with a11*x - x*a22 = a12, span the
+// invariant subspace of the second block and form the leading columns of the
+// orthogonal transformation.
+func swapSchurBlocks(t, z *Dense, j, p, q int) error {
+	n, _ := t.Dims()
+	s := p + q
+
+	a11, a12, a22 := &Dense{}, &Dense{}, &Dense{}
+	a11.Submatrix(t, j, j, p, p)
+	a12.Submatrix(t, j, j+p, p, q)
+	a22.Submatrix(t, j+p, j+p, q, q)
+	a22.Scale(-1, a22)
+	x, err := sylvesterBlock(a11, a22, a12)
+	if err != nil {
+		return err
+	}
+
+	m := NewDense(s, q, nil)
+	for i := 0; i < p; i++ {
+		for k := 0; k < q; k++ {
+			m.Set(i, k, -x.At(i, k))
+		}
+	}
+	for k := 0; k < q; k++ {
+		m.Set(p+k, k, 1)
+	}
+	h := householderBasis(m)
+
+	// Apply h'*t on the affected rows, t*h on the affected columns
+	// and z*h.
+	col := make([]float64, s)
+	for c := j; c < n; c++ {
+		for k := range col {
+			var v float64
+			for i := 0; i < s; i++ {
+				v += h.At(i, k) * t.At(j+i, c)
+			}
+			col[k] = v
+		}
+		for k, v := range col {
+			t.Set(j+k, c, v)
+		}
+	}
+	for _, w := range []*Dense{t, z} {
+		rows := j + s
+		if w == z {
+			rows, _ = z.Dims()
+		}
+		for r := 0; r < rows; r++ {
+			for k := range col {
+				var v float64
+				for i := 0; i < s; i++ {
+					v += w.At(r, j+i) * h.At(i, k)
+				}
+				col[k] = v
+			}
+			for k, v := range col {
+				w.Set(r, j+k, v)
+			}
+		}
+	}
+
+	// The block below the exchanged blocks is zero up to rounding.
+	for r := j + q; r < j+s; r++ {
+		for c := j; c < j+q; c++ {
+			t.Set(r, c, 0)
+		}
+	}
+	return nil
+}
+
+// householderBasis returns an orthogonal matrix whose leading columns span
+// the range of the tall matrix m of full column rank, formed as a product of
+// Householder reflections.
+func householderBasis(m *Dense) *Dense {
+	r, c := m.Dims()
+	a := DenseCopyOf(m)
+	h := newIdentity(r)
+	v := make([]float64, r)
+	for k := 0; k < c; k++ {
+		var norm float64
+		for i := k; i < r; i++ {
+			norm = math.Hypot(norm, a.At(i, k))
+		}
+		if norm == 0 {
+			continue
+		}
+		if a.At(k, k) > 0 {
+			norm = -norm
+		}
+		var vv float64
+		for i := k; i < r; i++ {
+			v[i] = a.At(i, k)
+			if i == k {
+				v[i] -= norm
+			}
+			vv += v[i] * v[i]
+		}
+
+		// Apply I - 2*v*v'/(v'*v) to the remaining columns of a on
+		// the left and accumulate it in h on the right.
+		for j := k; j < c; j++ {
+			var d float64
+			for i := k; i < r; i++ {
+				d += v[i] * a.At(i, j)
+			}
+			d *= 2 / vv
+			for i := k; i < r; i++ {
+				a.Set(i, j, a.At(i, j)-d*v[i])
+			}
+		}
+		for i := 0; i < r; i++ {
+			var d float64
+			for l := k; l < r; l++ {
+				d += h.At(i, l) * v[l]
+			}
+			d *= 2 / vv
+			for l := k; l < r; l++ {
+				h.Set(i, l, h.At(i, l)-d*v[l])
+			}
+		}
+	}
+	return h
+}
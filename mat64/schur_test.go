@@ -0,0 +1,78 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+)
+
+func (s *S) TestSchur(c *check.C) {
+	for i, test := range []struct {
+		a      *Dense
+		blocks []int
+	}{
+		{
+			a:      NewDense(3, 3, []float64{4, 1, 2, 0, 3, 1, 1, 0, 2}),
+			blocks: []int{0, 1, 2, 3},
+		},
+		{
+			a:      NewDense(3, 3, []float64{0, -2, 1, 2, 0, 3, 0, 0, 1}),
+			blocks: []int{0, 2, 3},
+		},
+		{
+			a: NewDense(4, 4, []float64{
+				1, 2, 3, 4,
+				-2, 1, 0, 1,
+				0, 5, -1, 2,
+				3, 0, 1, 2,
+			}),
+		},
+	} {
+		f := Schur(DenseCopyOf(test.a), epsilon)
+		c.Check(isOrthogonal(f.Z), check.Equals, true, check.Commentf("Test %d", i))
+
+		n, _ := f.T.Dims()
+		blk := schurBlocks(f.T)
+		if test.blocks != nil {
+			c.Check(blk, check.DeepEquals, test.blocks, check.Commentf("Test %d", i))
+		}
+		for b := 0; b < len(blk)-1; b++ {
+			for r := blk[b+1]; r < n; r++ {
+				for col := blk[b]; col < blk[b+1]; col++ {
+					c.Check(f.T.At(r, col), check.Equals, 0.,
+						check.Commentf("Test %d: t[%d][%d] below blocks", i, r, col))
+				}
+			}
+		}
+
+		c.Check(similarity(f.Z, f.T).EqualsApprox(test.a, 1e-12), check.Equals, true, check.Commentf("Test %d", i))
+	}
+
+	// Reordering moves the selected eigenvalues to the leading blocks
+	// and preserves the decomposition.
+	a := NewDense(5, 5, []float64{
+		1, 2, 0, 0.5, 1,
+		-2, 1, 0.3, 0, 0,
+		0, 0.4, -3, 1, 0.2,
+		0.1, 0, 0.5, 2, -4,
+		0, 0.2, 0, 4, 2,
+	})
+	f := Schur(DenseCopyOf(a), epsilon)
+	k, err := reorderSchur(f.T, f.Z, func(re, im float64) bool { return re < 1.5 })
+	c.Assert(err, check.Equals, nil)
+	c.Check(k, check.Equals, 3)
+	blk := schurBlocks(f.T)
+	for b := 0; b < len(blk)-1; b++ {
+		re, _ := blockValue(f.T, blk[b], blk[b+1]-blk[b])
+		c.Check(re < 1.5, check.Equals, blk[b] < k, check.Commentf("block at %d", blk[b]))
+		for r := blk[b+1]; r < 5; r++ {
+			for col := blk[b]; col < blk[b+1]; col++ {
+				c.Check(f.T.At(r, col), check.Equals, 0.)
+			}
+		}
+	}
+	c.Check(isOrthogonal(f.Z), check.Equals, true)
+	c.Check(similarity(f.Z, f.T).EqualsApprox(a, 1e-12), check.Equals, true)
+}
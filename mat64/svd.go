@@ -16,12 +16,36 @@ type SVDFactors struct {
 	m, n  int
 }
 
+// SVDJob specifies which singular vectors are formed by a singular value
+// decomposition of an m-by-n matrix.
+type SVDJob int
+
+const (
+	// SVDNone specifies that the singular vectors are not formed.
+	SVDNone SVDJob = iota
+	// SVDThin specifies that only the first min(m, n) singular vectors are formed.
+	SVDThin
+	// SVDFull specifies that the complete orthogonal matrix of singular vectors
+	// is formed.
+	SVDFull
+)
+
+// SVDWork holds storage that can be reused by repeated singular value
+// decompositions. The zero value is ready to use. Factors returned by
+// SVDWork.SVD share storage with the work value and are only valid until
+// the next call.
+type SVDWork struct {
+	a, u, v  []float64
+	sigma, e []float64
+	work     []float64
+}
+
 // SVD performs singular value decomposition for an m-by-n matrix a. The
 // singular value decomposition is an m-by-n orthogonal matrix u, an n-by-n
 // diagonal matrix s, and an n-by-n orthogonal matrix v so that a = u*s*v'. If
-// a is a wide matrix a copy of its transpose is allocated, otherwise a is
-// overwritten during the decomposition. Matrices u and v are only created when
-// wantu and wantv are true respectively.
+// a is a wide matrix a copy of its transpose is allocated and a is left
+// unaltered, otherwise a is overwritten during the decomposition. Matrices u
+// and v are only created when wantu and wantv are true respectively.
 //
 // The singular values, sigma[k] = s[k][k], are ordered so that
 //
@@ -30,29 +54,76 @@ type SVDFactors struct {
 // The matrix condition number and the effective numerical rank can be computed from
 // this decomposition.
 func SVD(a *Dense, epsilon, small float64, wantu, wantv bool) SVDFactors {
+	jobu, jobv := SVDNone, SVDNone
+	if wantu {
+		jobu = SVDThin
+	}
+	if wantv {
+		jobv = SVDThin
+	}
+	var w SVDWork
+	return w.SVD(a, epsilon, small, jobu, jobv, false)
+}
+
+// SVDJobs performs singular value decomposition for an m-by-n matrix a as SVD
+// does, forming u and v according to jobu and jobv. For SVDThin u is m-by-min(m, n)
+// and v is n-by-min(m, n), and for SVDFull u is m-by-m and v is n-by-n. If
+// preserve is true, the decomposition is performed on a copy and a is left
+// unaltered.
+func SVDJobs(a *Dense, epsilon, small float64, jobu, jobv SVDJob, preserve bool) SVDFactors {
+	var w SVDWork
+	return w.SVD(a, epsilon, small, jobu, jobv, preserve)
+}
+
+// SVD performs singular value decomposition for an m-by-n matrix a as SVDJobs
+// does, using the storage held by the receiver and allocating only when it is
+// too small for the decomposition.
+func (w *SVDWork) SVD(a *Dense, epsilon, small float64, jobu, jobv SVDJob, preserve bool) SVDFactors {
 	m, n := a.Dims()
 
 	trans := false
 	if m < n {
-		a.TCopy(a)
 		m, n = n, m
-		wantu, wantv = wantv, wantu
+		jobu, jobv = jobv, jobu
 		trans = true
 	}
+	switch {
+	case trans:
+		w.a = use(w.a, m*n)
+		t := NewDense(m, n, w.a)
+		t.TCopy(a)
+		a = t
+	case preserve:
+		w.a = use(w.a, m*n)
+		t := NewDense(m, n, w.a)
+		t.Copy(a)
+		a = t
+	}
+
+	wantu := jobu != SVDNone
+	wantv := jobv != SVDNone
 
-	sigma := make([]float64, min(m+1, n))
+	w.sigma = useZero(w.sigma, min(m+1, n))
+	sigma := w.sigma
 	nu := min(m, n)
+	if jobu == SVDFull {
+		nu = m
+	}
 	var u, v *Dense
 	if wantu {
-		u = NewDense(m, nu, nil)
+		w.u = useZero(w.u, m*nu)
+		u = NewDense(m, nu, w.u)
 	}
 	if wantv {
-		v = NewDense(n, n, nil)
+		w.v = useZero(w.v, n*n)
+		v = NewDense(n, n, w.v)
 	}
 
+	w.e = useZero(w.e, n)
+	w.work = useZero(w.work, m)
 	var (
-		e    = make([]float64, n)
-		work = make([]float64, m)
+		e    = w.e
+		work = w.work
 	)
 
 	// Reduce a to bidiagonal form, storing the diagonal elements
@@ -204,7 +275,7 @@ func SVD(a *Dense, epsilon, small float64, wantu, wantv bool) SVDFactors {
 	if wantv {
 		for k := n - 1; k >= 0; k-- {
 			if k < nrt && e[k] != 0 {
-				for j := k + 1; j < nu; j++ {
+				for j := k + 1; j < n; j++ {
 					var t float64
 					for i := k + 1; i < n; i++ {
 						t += v.At(i, k) * v.At(i, j)
@@ -477,3 +548,110 @@ func (f SVDFactors) Rank(epsilon float64) int {
 func (f SVDFactors) Cond() float64 {
 	return f.Sigma[0] / f.Sigma[min(f.m, f.n)-1]
 }
+
+// PseudoInverse returns the Moore-Penrose pseudoinverse of the factorised matrix,
+// v*pinv(s)*u', where singular values judged negligible by Rank with the given
+// epsilon are treated as zero. PseudoInverse will panic with ErrNoVectors if u
+// or v were not formed.
+func (f SVDFactors) PseudoInverse(epsilon float64) *Dense {
+	if f.U == nil || f.V == nil {
+		panic(ErrNoVectors)
+	}
+	r := f.Rank(epsilon)
+	m, _ := f.U.Dims()
+	n, _ := f.V.Dims()
+	if r == 0 {
+		return NewDense(n, m, nil)
+	}
+
+	var ur, vr Dense
+	ur.View(f.U, 0, 0, m, r)
+	vr.View(f.V, 0, 0, n, r)
+	vs := &Dense{}
+	vs.Clone(&vr)
+	for j, s := range f.Sigma[:r] {
+		for i := 0; i < n; i++ {
+			vs.Set(i, j, vs.At(i, j)/s)
+		}
+	}
+	ut := &Dense{}
+	ut.TCopy(&ur)
+	p := &Dense{}
+	p.Mul(vs, ut)
+	return p
+}
+
+// Range returns a newly allocated matrix whose columns are an orthonormal basis
+// for the range of the factorised matrix, the leading columns of u corresponding
+// to the singular values judged non-negligible by Rank with the given epsilon.
+// Range returns nil if the rank is zero and will panic with ErrNoVectors if u
+// was not formed.
+func (f SVDFactors) Range(epsilon float64) *Dense {
+	if f.U == nil {
+		panic(ErrNoVectors)
+	}
+	r := f.Rank(epsilon)
+	if r == 0 {
+		return nil
+	}
+	m, _ := f.U.Dims()
+	b := &Dense{}
+	b.Submatrix(f.U, 0, 0, m, r)
+	return b
+}
+
+// NullSpace returns a newly allocated matrix whose columns are an orthonormal
+// basis for the null space of the factorised matrix, the columns of v beyond the
+// rank determined by Rank with the given epsilon. NullSpace returns nil if the
+// null space is trivial and will panic with ErrNoVectors if v is not square, that
+// is if v was not formed or, for a wide matrix, was not formed with SVDFull.
+func (f SVDFactors) NullSpace(epsilon float64) *Dense {
+	if f.V == nil {
+		panic(ErrNoVectors)
+	}
+	n, c := f.V.Dims()
+	if c != n {
+		panic(ErrNoVectors)
+	}
+	r := f.Rank(epsilon)
+	if r == n {
+		return nil
+	}
+	b := &Dense{}
+	b.Submatrix(f.V, 0, r, n, n-r)
+	return b
+}
+
+// Truncated returns the best rank k approximation, u_k*s_k*v_k', of the factorised
+// matrix formed from the k leading singular triplets. Truncated will panic with
+// ErrNoVectors if u or v were not formed, and with ErrIndexOutOfRange if k is
+// negative or exceeds the number of singular values.
+func (f SVDFactors) Truncated(k int) *Dense {
+	if f.U == nil || f.V == nil {
+		panic(ErrNoVectors)
+	}
+	if k < 0 || k > len(f.Sigma) {
+		panic(indexError("SVDFactors.Truncated", f.U, k))
+	}
+	m, _ := f.U.Dims()
+	n, _ := f.V.Dims()
+	if k == 0 {
+		return NewDense(m, n, nil)
+	}
+
+	var uk, vk Dense
+	uk.View(f.U, 0, 0, m, k)
+	vk.View(f.V, 0, 0, n, k)
+	us := &Dense{}
+	us.Clone(&uk)
+	for j, s := range f.Sigma[:k] {
+		for i := 0; i < m; i++ {
+			us.Set(i, j, us.At(i, j)*s)
+		}
+	}
+	vt := &Dense{}
+	vt.TCopy(&vk)
+	a := &Dense{}
+	a.Mul(us, vt)
+	return a
+}
@@ -199,3 +199,203 @@ func (s *S) TestSVD(c *check.C) {
 		}
 	}
 }
+
+func (s *S) TestSVDJobs(c *check.C) {
+	for i, test := range []struct {
+		a          *Dense
+		jobu, jobv SVDJob
+
+		ur, uc int
+		vr, vc int
+	}{
+		{
+			a:    NewDense(4, 2, []float64{2, 4, 1, 3, 0, 0, 0, 0}),
+			jobu: SVDFull, jobv: SVDFull,
+			ur: 4, uc: 4,
+			vr: 2, vc: 2,
+		},
+		{
+			a:    NewDense(4, 2, []float64{2, 4, 1, 3, 0, 0, 0, 0}),
+			jobu: SVDThin, jobv: SVDNone,
+			ur: 4, uc: 2,
+		},
+		{
+			a: NewDense(3, 5, []float64{
+				1, 1, 0, 1, 11,
+				1, 0, 0, 0, 12,
+				1, 1, 0, 2, 13,
+			}),
+			jobu: SVDThin, jobv: SVDFull,
+			ur: 3, uc: 3,
+			vr: 5, vc: 5,
+		},
+		{
+			a: NewDense(3, 5, []float64{
+				1, 1, 0, 1, 11,
+				1, 0, 0, 0, 12,
+				1, 1, 0, 2, 13,
+			}),
+			jobu: SVDNone, jobv: SVDThin,
+			vr: 5, vc: 3,
+		},
+	} {
+		var w SVDWork
+		a := DenseCopyOf(test.a)
+		for j := 0; j < 2; j++ {
+			svd := w.SVD(a, math.Pow(2, -52.0), math.Pow(2, -966.0), test.jobu, test.jobv, true)
+			c.Check(a.Equals(test.a), check.Equals, true, check.Commentf("Test %d: input altered", i))
+
+			want := SVD(DenseCopyOf(test.a), math.Pow(2, -52.0), math.Pow(2, -966.0), false, false)
+			c.Check(svd.Sigma, check.DeepEquals, want.Sigma, check.Commentf("Test %d", i))
+
+			for _, f := range []struct {
+				m      *Dense
+				job    SVDJob
+				mr, mc int
+			}{
+				{svd.U, test.jobu, test.ur, test.uc},
+				{svd.V, test.jobv, test.vr, test.vc},
+			} {
+				if f.job == SVDNone {
+					c.Check(f.m, check.IsNil, check.Commentf("Test %d", i))
+					continue
+				}
+				r, cols := f.m.Dims()
+				c.Check(r, check.Equals, f.mr, check.Commentf("Test %d", i))
+				c.Check(cols, check.Equals, f.mc, check.Commentf("Test %d", i))
+
+				var mtm, ft Dense
+				ft.TCopy(f.m)
+				mtm.Mul(&ft, f.m)
+				eye := NewDense(cols, cols, nil)
+				for k := 0; k < cols; k++ {
+					eye.Set(k, k, 1)
+				}
+				c.Check(mtm.EqualsApprox(eye, 1e-12), check.Equals, true, check.Commentf("Test %d: not orthogonal", i))
+			}
+
+			if svd.U != nil && svd.V != nil {
+				m, n := test.a.Dims()
+				k := min(m, n)
+				var u, v, vt, us Dense
+				u.View(svd.U, 0, 0, m, k)
+				v.View(svd.V, 0, 0, n, k)
+				vt.TCopy(&v)
+				us.Mul(&u, svd.S())
+				us.Mul(&us, &vt)
+				c.Check(us.EqualsApprox(test.a, 1e-12), check.Equals, true, check.Commentf("Test %d", i))
+			}
+		}
+	}
+
+	a := NewDense(3, 5, []float64{
+		1, 1, 0, 1, 11,
+		1, 0, 0, 0, 12,
+		1, 1, 0, 2, 13,
+	})
+	orig := DenseCopyOf(a)
+	SVD(a, math.Pow(2, -52.0), math.Pow(2, -966.0), true, true)
+	c.Check(a.Equals(orig), check.Equals, true)
+}
+
+func (s *S) TestSVDSubspaces(c *check.C) {
+	for i, test := range []struct {
+		a    *Dense
+		rank int
+	}{
+		{
+			a: NewDense(4, 3, []float64{
+				1, 2, 3,
+				2, 4, 6,
+				1, 0, 1,
+				0, 1, 1,
+			}),
+			rank: 2,
+		},
+		{
+			a: NewDense(3, 5, []float64{
+				1, 1, 0, 1, 11,
+				1, 0, 0, 0, 12,
+				2, 1, 0, 1, 23,
+			}),
+			rank: 2,
+		},
+		{
+			a: NewDense(3, 3, []float64{
+				4, 1, 1,
+				1, 2, 3,
+				1, 3, 6,
+			}),
+			rank: 3,
+		},
+	} {
+		m, n := test.a.Dims()
+		svd := SVDJobs(test.a, math.Pow(2, -52.0), math.Pow(2, -966.0), SVDThin, SVDFull, true)
+		eps := math.Pow(2, -52.0)
+		c.Check(svd.Rank(eps), check.Equals, test.rank, check.Commentf("Test %d", i))
+
+		// Check the Moore-Penrose conditions.
+		x := svd.PseudoInverse(eps)
+		xr, xc := x.Dims()
+		c.Check([]int{xr, xc}, check.DeepEquals, []int{n, m}, check.Commentf("Test %d", i))
+		var ax, xa, axa, xax, t Dense
+		ax.Mul(test.a, x)
+		xa.Mul(x, test.a)
+		axa.Mul(&ax, test.a)
+		xax.Mul(&xa, x)
+		c.Check(axa.EqualsApprox(test.a, 1e-12), check.Equals, true, check.Commentf("Test %d: a*x*a != a", i))
+		c.Check(xax.EqualsApprox(x, 1e-12), check.Equals, true, check.Commentf("Test %d: x*a*x != x", i))
+		t.TCopy(&ax)
+		c.Check(t.EqualsApprox(&ax, 1e-12), check.Equals, true, check.Commentf("Test %d: a*x not symmetric", i))
+		t = Dense{}
+		t.TCopy(&xa)
+		c.Check(t.EqualsApprox(&xa, 1e-12), check.Equals, true, check.Commentf("Test %d: x*a not symmetric", i))
+		c.Check(Inverse(test.a).EqualsApprox(x, 1e-12), check.Equals, true, check.Commentf("Test %d", i))
+
+		r := svd.Range(eps)
+		_, rc := r.Dims()
+		c.Check(rc, check.Equals, test.rank, check.Commentf("Test %d", i))
+		c.Check(isOrthogonal(r), check.Equals, true, check.Commentf("Test %d", i))
+		// The range is invariant under projection onto it.
+		var rt, p, pa Dense
+		rt.TCopy(r)
+		p.Mul(r, &rt)
+		pa.Mul(&p, test.a)
+		c.Check(pa.EqualsApprox(test.a, 1e-12), check.Equals, true, check.Commentf("Test %d", i))
+
+		ns := svd.NullSpace(eps)
+		if test.rank == n {
+			c.Check(ns, check.IsNil, check.Commentf("Test %d", i))
+		} else {
+			_, nc := ns.Dims()
+			c.Check(nc, check.Equals, n-test.rank, check.Commentf("Test %d", i))
+			c.Check(isOrthogonal(ns), check.Equals, true, check.Commentf("Test %d", i))
+			var an Dense
+			an.Mul(test.a, ns)
+			c.Check(an.EqualsApprox(NewDense(m, nc, nil), 1e-12), check.Equals, true, check.Commentf("Test %d", i))
+		}
+
+		c.Check(svd.Truncated(test.rank).EqualsApprox(test.a, 1e-12), check.Equals, true, check.Commentf("Test %d", i))
+		t1 := svd.Truncated(1)
+		var d Dense
+		d.Sub(test.a, t1)
+		c.Check(math.Abs(d.Norm(2)-svd.Sigma[1]) < 1e-12, check.Equals, true, check.Commentf("Test %d", i))
+	}
+
+	c.Check(func() { SVD(eye(), math.Pow(2, -52.0), math.Pow(2, -966.0), false, true).PseudoInverse(1e-12) }, check.PanicMatches, string(ErrNoVectors))
+
+	// A thin v of a wide matrix cannot span its null space.
+	wide := NewDense(2, 3, []float64{
+		1, 0, 0,
+		0, 1, 0,
+	})
+	thin := SVDJobs(wide, math.Pow(2, -52.0), math.Pow(2, -966.0), SVDThin, SVDThin, true)
+	c.Check(func() { thin.NullSpace(1e-12) }, check.PanicMatches, string(ErrNoVectors))
+	full := SVDJobs(wide, math.Pow(2, -52.0), math.Pow(2, -966.0), SVDThin, SVDFull, true)
+	ns := full.NullSpace(1e-12)
+	nr, nc := ns.Dims()
+	c.Check([]int{nr, nc}, check.DeepEquals, []int{3, 1})
+	var an Dense
+	an.Mul(wide, ns)
+	c.Check(an.EqualsApprox(NewDense(2, 1, nil), 1e-12), check.Equals, true)
+}
@@ -0,0 +1,274 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+// Sylvester returns the solution x of the Sylvester equation a*x + x*b = c for
+// an m-by-m matrix a, an n-by-n matrix b and an m-by-n matrix c, computed by
+// the Bartels-Stewart method. With the real Schur decompositions a = u*s*u' and
+// b = v*t*v', the equation becomes s*y + y*t = u'*c*v, which is solved for
+// y = u'*x*v one diagonal block of s and t at a time.
+//
+// Sylvester returns ErrSingular if a and -b have an eigenvalue in common, in
+// which case the solution is not unique.
+func Sylvester(a, b, c Matrix) (*Dense, error) {
+	am, an := a.Dims()
+	bm, bn := b.Dims()
+	if am != an || bm != bn {
+		panic(opError(ErrSquare, "Sylvester", a, b, c))
+	}
+	if cm, cn := c.Dims(); cm != am || cn != bm {
+		panic(opError(ErrShape, "Sylvester", a, b, c))
+	}
+
+	sa := Schur(DenseCopyOf(a), epsilon)
+	sb := Schur(DenseCopyOf(b), epsilon)
+	y, err := sylvesterQuasi(sa.T, sb.T, schurReduce(sa.Z, c, sb.Z))
+	if err != nil {
+		return nil, err
+	}
+	return schurRestore(sa.Z, y, sb.Z), nil
+}
+
+// Lyapunov returns the solution x of the continuous Lyapunov equation
+// a*x + x*a' + q = 0 for square matrices a and q of the same order. If q is
+// symmetric so is x, and if in addition all eigenvalues of a have negative real
+// parts and q is positive definite, x is positive definite.
+//
+// Lyapunov returns ErrSingular if a and -a' have an eigenvalue in common.
+func Lyapunov(a, q Matrix) (*Dense, error) {
+	checkLyapunov("Lyapunov", a, q)
+
+	var at, nq Dense
+	at.TCopy(a)
+	nq.Scale(-1, q)
+	x, err := Sylvester(a, &at, &nq)
+	if err != nil {
+		return nil, err
+	}
+	if symmetricApprox(q) {
+		symmetrize(x)
+	}
+	return x, nil
+}
+
+// DiscreteLyapunov returns the solution x of the discrete Lyapunov, or Stein,
+// equation a*x*a' - x + q = 0 for square matrices a and q of the same order,
+// computed from the real Schur decomposition a = u*s*u' by solving
+// s*y*s' - y = -u'*q*u one diagonal block of s at a time. If q is symmetric so
+// is x, and if in addition all eigenvalues of a lie inside the unit circle and q
+// is positive definite, x is positive definite.
+//
+// DiscreteLyapunov returns ErrSingular if a has eigenvalues whose product is one.
+func DiscreteLyapunov(a, q Matrix) (*Dense, error) {
+	checkLyapunov("DiscreteLyapunov", a, q)
+
+	sa := Schur(DenseCopyOf(a), epsilon)
+	f := schurReduce(sa.Z, q, sa.Z)
+	f.Scale(-1, f)
+	y, err := steinQuasi(sa.T, f)
+	if err != nil {
+		return nil, err
+	}
+	x := schurRestore(sa.Z, y, sa.Z)
+	if symmetricApprox(q) {
+		symmetrize(x)
+	}
+	return x, nil
+}
+
+func checkLyapunov(op string, a, q Matrix) {
+	am, an := a.Dims()
+	qm, qn := q.Dims()
+	if am != an || qm != qn {
+		panic(opError(ErrSquare, op, a, q))
+	}
+	if am != qm {
+		panic(opError(ErrShape, op, a, q))
+	}
+}
+
+// symmetricApprox returns whether the square matrix a is symmetric to within
+// rounding error.
+func symmetricApprox(a Matrix) bool {
+	n, _ := a.Dims()
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			aij, aji := a.At(i, j), a.At(j, i)
+			if aij != aji && (aij-aji)*(aij-aji) > epsilon*epsilon*(aij*aij+aji*aji) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// schurReduce returns u'*c*v.
+func schurReduce(u *Dense, c Matrix, v *Dense) *Dense {
+	var ut Dense
+	ut.TCopy(u)
+	f := &Dense{}
+	f.Mul(&ut, c)
+	f.Mul(f, v)
+	return f
+}
+
+// schurRestore returns u*y*v'.
+func schurRestore(u, y, v *Dense) *Dense {
+	var vt Dense
+	vt.TCopy(v)
+	x := &Dense{}
+	x.Mul(u, y)
+	x.Mul(x, &vt)
+	return x
+}
+
+// sylvesterQuasi returns the solution y of s*y + y*t = f for upper
+// quasi-triangular s and t. Block columns of y are found from left to right
+// and the blocks within each column from the bottom up.
+func sylvesterQuasi(s, t, f *Dense) (*Dense, error) {
+	m, n := f.Dims()
+	y := NewDense(m, n, nil)
+	sb := schurBlocks(s)
+	tb := schurBlocks(t)
+
+	for jb := 0; jb < len(tb)-1; jb++ {
+		j, q := tb[jb], tb[jb+1]-tb[jb]
+		tjj := &Dense{}
+		tjj.Submatrix(t, j, j, q, q)
+		for ib := len(sb) - 2; ib >= 0; ib-- {
+			i, p := sb[ib], sb[ib+1]-sb[ib]
+			c := NewDense(p, q, nil)
+			for k := 0; k < p; k++ {
+				for l := 0; l < q; l++ {
+					v := f.At(i+k, j+l)
+					for h := i + p; h < m; h++ {
+						v -= s.At(i+k, h) * y.At(h, j+l)
+					}
+					for h := 0; h < j; h++ {
+						v -= y.At(i+k, h) * t.At(h, j+l)
+					}
+					c.Set(k, l, v)
+				}
+			}
+			sii := &Dense{}
+			sii.Submatrix(s, i, i, p, p)
+			x, err := sylvesterBlock(sii, tjj, c)
+			if err != nil {
+				return nil, err
+			}
+			for k := 0; k < p; k++ {
+				for l := 0; l < q; l++ {
+					y.Set(i+k, j+l, x.At(k, l))
+				}
+			}
+		}
+	}
+
+	return y, nil
+}
+
+// steinQuasi returns the solution y of s*y*s' - y = f for upper
+// quasi-triangular s. Block columns of y are found from right to left and the
+// blocks within each column from the bottom up, keeping the products z = y*s'
+// for the blocks already found.
+func steinQuasi(s, f *Dense) (*Dense, error) {
+	n, _ := f.Dims()
+	y := NewDense(n, n, nil)
+	z := NewDense(n, n, nil)
+	var t Dense
+	t.TCopy(s)
+	blk := schurBlocks(s)
+
+	for jb := len(blk) - 2; jb >= 0; jb-- {
+		j, q := blk[jb], blk[jb+1]-blk[jb]
+		tjj := &Dense{}
+		tjj.Submatrix(&t, j, j, q, q)
+		for ib := len(blk) - 2; ib >= 0; ib-- {
+			i, p := blk[ib], blk[ib+1]-blk[ib]
+
+			// Form the part of z in this block from the blocks
+			// of y to its right, and the right hand side
+			// f_ij - sum_{k>=i} s_ik*z_kj.
+			for k := 0; k < p; k++ {
+				for l := 0; l < q; l++ {
+					var v float64
+					for h := j + q; h < n; h++ {
+						v += y.At(i+k, h) * t.At(h, j+l)
+					}
+					z.Set(i+k, j+l, v)
+				}
+			}
+			c := NewDense(p, q, nil)
+			for k := 0; k < p; k++ {
+				for l := 0; l < q; l++ {
+					v := f.At(i+k, j+l)
+					for h := i; h < n; h++ {
+						v -= s.At(i+k, h) * z.At(h, j+l)
+					}
+					c.Set(k, l, v)
+				}
+			}
+
+			sii := &Dense{}
+			sii.Submatrix(s, i, i, p, p)
+			x, err := steinBlock(sii, tjj, c)
+			if err != nil {
+				return nil, err
+			}
+			for k := 0; k < p; k++ {
+				for l := 0; l < q; l++ {
+					y.Set(i+k, j+l, x.At(k, l))
+				}
+			}
+
+			// Complete z in this block with the contribution of
+			// the block just found.
+			for k := 0; k < p; k++ {
+				for l := 0; l < q; l++ {
+					v := z.At(i+k, j+l)
+					for h := j; h < j+q; h++ {
+						v += y.At(i+k, h) * t.At(h, j+l)
+					}
+					z.Set(i+k, j+l, v)
+				}
+			}
+		}
+	}
+
+	return y, nil
+}
+
+// steinBlock returns the solution x of a*x*b - x = c for the small square
+// matrices a and b by solving the equivalent Kronecker product system.
+func steinBlock(a, b, c *Dense) (*Dense, error) {
+	p, _ := a.Dims()
+	q, _ := b.Dims()
+	k := NewDense(p*q, p*q, nil)
+	rhs := NewDense(p*q, 1, nil)
+	for i := 0; i < p; i++ {
+		for j := 0; j < q; j++ {
+			row := i*q + j
+			for l := 0; l < p; l++ {
+				for h := 0; h < q; h++ {
+					k.Set(row, l*q+h, a.At(i, l)*b.At(h, j))
+				}
+			}
+			k.Set(row, row, k.At(row, row)-1)
+			rhs.Set(row, 0, c.At(i, j))
+		}
+	}
+	lu := LU(k)
+	if lu.IsSingular() {
+		return nil, ErrSingular
+	}
+	y := lu.Solve(rhs)
+	x := NewDense(p, q, nil)
+	for i := 0; i < p; i++ {
+		for j := 0; j < q; j++ {
+			x.Set(i, j, y.At(i*q+j, 0))
+		}
+	}
+	return x, nil
+}
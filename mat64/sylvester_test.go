@@ -0,0 +1,148 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+)
+
+func (s *S) TestSylvester(c *check.C) {
+	for i, test := range []struct {
+		a, b, c *Dense
+		err     error
+	}{
+		{
+			a: NewDense(2, 2, []float64{1, 2, 0, 3}),
+			b: NewDense(1, 1, []float64{4}),
+			c: NewDense(2, 1, []float64{1, -1}),
+		},
+		{
+			// a has complex eigenvalues and b a mixture.
+			a: NewDense(3, 3, []float64{
+				1, -2, 0.5,
+				2, 1, 0,
+				0.3, 0.1, 4,
+			}),
+			b: NewDense(4, 4, []float64{
+				2, 0, 1, 0.5,
+				0, 0.5, -3, 0,
+				0.2, 3, 0.5, 1,
+				0, 0, 0.1, -1.5,
+			}),
+			c: NewDense(3, 4, []float64{
+				1, 2, 3, 4,
+				-1, 0, 2, 1,
+				0.5, 0.5, -2, 3,
+			}),
+		},
+		{
+			a:   NewDense(2, 2, []float64{1, 0, 0, 2}),
+			b:   NewDense(2, 2, []float64{-2, 1, 0, 3}),
+			c:   NewDense(2, 2, []float64{1, 1, 1, 1}),
+			err: ErrSingular,
+		},
+	} {
+		x, err := Sylvester(test.a, test.b, test.c)
+		c.Check(err, check.Equals, test.err, check.Commentf("Test %d", i))
+		if test.err != nil {
+			continue
+		}
+		var ax, xb Dense
+		ax.Mul(test.a, x)
+		xb.Mul(x, test.b)
+		ax.Add(&ax, &xb)
+		ax.Sub(&ax, test.c)
+		c.Check(ax.Norm(0) <= 1e-13*test.c.Norm(0), check.Equals, true,
+			check.Commentf("Test %d: residual %v", i, ax.Norm(0)))
+	}
+}
+
+func (s *S) TestLyapunov(c *check.C) {
+	for i, test := range []struct {
+		a, q *Dense
+	}{
+		{
+			a: NewDense(2, 2, []float64{-1, 2, 0, -3}),
+			q: NewDense(2, 2, []float64{1, 0, 0, 1}),
+		},
+		{
+			a: NewDense(4, 4, []float64{
+				-0.5, 2, 0, 0.1,
+				-2, -0.5, 0.3, 0,
+				0, 0.1, -1, 0.4,
+				0.2, 0, 0, -2,
+			}),
+			q: NewDense(4, 4, []float64{
+				2, 1, 0, 0,
+				1, 2, 1, 0,
+				0, 1, 2, 1,
+				0, 0, 1, 2,
+			}),
+		},
+	} {
+		x, err := Lyapunov(test.a, test.q)
+		c.Assert(err, check.Equals, nil, check.Commentf("Test %d", i))
+		c.Check(symmetric(x), check.Equals, true, check.Commentf("Test %d", i))
+		c.Check(Cholesky(DenseCopyOf(x)).SPD, check.Equals, true, check.Commentf("Test %d", i))
+
+		var ax, xat, at Dense
+		at.TCopy(test.a)
+		ax.Mul(test.a, x)
+		xat.Mul(x, &at)
+		ax.Add(&ax, &xat)
+		ax.Add(&ax, test.q)
+		c.Check(ax.Norm(0) <= 1e-13*test.q.Norm(0), check.Equals, true,
+			check.Commentf("Test %d: residual %v", i, ax.Norm(0)))
+	}
+}
+
+func (s *S) TestDiscreteLyapunov(c *check.C) {
+	for i, test := range []struct {
+		a, q *Dense
+		err  error
+	}{
+		{
+			a: NewDense(2, 2, []float64{0.5, 1, 0, -0.3}),
+			q: NewDense(2, 2, []float64{1, 0, 0, 1}),
+		},
+		{
+			// a has complex eigenvalues inside the unit circle.
+			a: NewDense(4, 4, []float64{
+				0.3, -0.6, 0.1, 0,
+				0.6, 0.3, 0, 0.2,
+				0, 0.1, -0.5, 0.3,
+				0.1, 0, 0.2, 0.8,
+			}),
+			q: NewDense(4, 4, []float64{
+				2, 1, 0, 0,
+				1, 2, 1, 0,
+				0, 1, 2, 1,
+				0, 0, 1, 2,
+			}),
+		},
+		{
+			a:   NewDense(2, 2, []float64{2, 0, 1, 0.5}),
+			q:   NewDense(2, 2, []float64{1, 0, 0, 1}),
+			err: ErrSingular,
+		},
+	} {
+		x, err := DiscreteLyapunov(test.a, test.q)
+		c.Check(err, check.Equals, test.err, check.Commentf("Test %d", i))
+		if test.err != nil {
+			continue
+		}
+		c.Check(symmetric(x), check.Equals, true, check.Commentf("Test %d", i))
+		c.Check(Cholesky(DenseCopyOf(x)).SPD, check.Equals, true, check.Commentf("Test %d", i))
+
+		var axat, at Dense
+		at.TCopy(test.a)
+		axat.Mul(test.a, x)
+		axat.Mul(&axat, &at)
+		axat.Sub(&axat, x)
+		axat.Add(&axat, test.q)
+		c.Check(axat.Norm(0) <= 1e-13*test.q.Norm(0), check.Equals, true,
+			check.Commentf("Test %d: residual %v", i, axat.Norm(0)))
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+)
+
+// tikhonovLambdas is the number of regularization parameters in the default
+// grid searched by MinGCV and LCorner.
+const tikhonovLambdas = 200
+
+// Tikhonov holds the singular value decomposition of a Tikhonov regularized
+// least squares problem
+//  min ||a*x - b||^2 + lambda^2*||l*x||^2
+// in standard form, from which the solution, its norms and the parameter
+// choice criteria are formed cheaply for any value of lambda.
+//
+// For a general regularization operator l the problem is transformed to the
+// standard form min ||abar*xbar - bbar||^2 + lambda^2*||xbar||^2 with the
+// a-weighted pseudoinverse of l, as described by Hansen, "Rank-Deficient and
+// Discrete Ill-Posed Problems", SIAM, 1998, section 2.3, so that
+// x = pinv_a(l)*xbar + x0, where x0 is the component of x in the null space of
+// l, and ||l*x|| = ||xbar||.
+type Tikhonov struct {
+	svd SVDFactors
+
+	// beta holds u'*bbar and delta the norm of the part of bbar
+	// outside the range of abar.
+	beta  []float64
+	delta float64
+
+	// la is the a-weighted pseudoinverse of l and x0 the
+	// unregularized component of the solution, both nil for l = I.
+	la, x0 *Dense
+
+	// m is the number of rows of a and q the dimension of the null
+	// space of l.
+	m, q int
+}
+
+// NewTikhonov returns the Tikhonov regularized least squares problem for the
+// m-by-n matrix a, the m-by-1 matrix b and the regularization operator l. If l
+// is nil the identity is used, giving ridge regression. Otherwise l must have n
+// columns and full rank; an l with more rows than columns is replaced by the
+// triangular factor of its QR decomposition.
+//
+// NewTikhonov returns ErrSingular if l does not have full rank or if the null
+// spaces of a and l intersect, in which case the regularized solution is not
+// unique.
+func NewTikhonov(a, l, b Matrix) (*Tikhonov, error) {
+	m, n := a.Dims()
+	if bm, bn := b.Dims(); bm != m || bn != 1 {
+		panic(opError(ErrShape, "NewTikhonov", a, b))
+	}
+
+	t := &Tikhonov{m: m}
+	abar := DenseCopyOf(a)
+	bbar := DenseCopyOf(b)
+	if l != nil {
+		p, ln := l.Dims()
+		if ln != n {
+			panic(opError(ErrShape, "NewTikhonov", a, l))
+		}
+		ld := DenseCopyOf(l)
+		if p > n {
+			f := QR(ld)
+			if !f.IsFullRank() {
+				return nil, ErrSingular
+			}
+			ld, p = f.R(), n
+		}
+
+		lf := SVDJobs(ld, epsilon, small, SVDThin, SVDFull, false)
+		if lf.Rank(epsilon) < p {
+			return nil, ErrSingular
+		}
+		t.la = lf.PseudoInverse(epsilon)
+		t.q = n - p
+		if t.q > 0 {
+			// With nu a basis for the null space of l and
+			// tp = pinv(a*nu), pinv_a(l) = (I - nu*tp*a)*pinv(l)
+			// and x0 = nu*tp*b.
+			nu := lf.NullSpace(epsilon)
+			an := &Dense{}
+			an.Mul(a, nu)
+			af := SVDJobs(an, epsilon, small, SVDThin, SVDThin, false)
+			tol := float64(max(m, n)) * epsilon * abar.Norm(0)
+			if len(af.Sigma) < t.q || af.Sigma[t.q-1] <= tol {
+				return nil, ErrSingular
+			}
+			tp := af.PseudoInverse(epsilon)
+			nt := &Dense{}
+			nt.Mul(nu, tp)
+
+			nta := &Dense{}
+			nta.Mul(nt, a)
+			proj := &Dense{}
+			proj.Mul(nta, t.la)
+			t.la.Sub(t.la, proj)
+
+			t.x0 = &Dense{}
+			t.x0.Mul(nt, b)
+			ax0 := &Dense{}
+			ax0.Mul(a, t.x0)
+			bbar.Sub(bbar, ax0)
+		}
+		abar = &Dense{}
+		abar.Mul(a, t.la)
+	}
+
+	t.svd = SVDJobs(abar, epsilon, small, SVDThin, SVDThin, false)
+	k := len(t.svd.Sigma)
+	t.beta = make([]float64, k)
+	r := make([]float64, m)
+	for i := range r {
+		r[i] = bbar.At(i, 0)
+	}
+	for j := range t.beta {
+		for i := 0; i < m; i++ {
+			t.beta[j] += t.svd.U.At(i, j) * bbar.At(i, 0)
+		}
+		for i := range r {
+			r[i] -= t.beta[j] * t.svd.U.At(i, j)
+		}
+	}
+	for _, v := range r {
+		t.delta = math.Hypot(t.delta, v)
+	}
+	return t, nil
+}
+
+// filter returns the Tikhonov filter factor sigma^2/(sigma^2 + lambda^2).
+func filter(sigma, lambda float64) float64 {
+	if sigma == 0 {
+		return 0
+	}
+	s2 := sigma * sigma
+	return s2 / (s2 + lambda*lambda)
+}
+
+// Solve returns the regularized solution x for the given lambda.
+func (t *Tikhonov) Solve(lambda float64) *Dense {
+	v := t.svd.V
+	p, _ := v.Dims()
+	xbar := NewDense(p, 1, nil)
+	for j, s := range t.svd.Sigma {
+		f := filter(s, lambda)
+		if f == 0 {
+			continue
+		}
+		c := f * t.beta[j] / s
+		for i := 0; i < p; i++ {
+			xbar.Set(i, 0, xbar.At(i, 0)+c*v.At(i, j))
+		}
+	}
+	if t.la == nil {
+		return xbar
+	}
+
+	x := &Dense{}
+	x.Mul(t.la, xbar)
+	if t.x0 != nil {
+		x.Add(x, t.x0)
+	}
+	return x
+}
+
+// Sweep returns the regularized solutions for each of the given lambdas.
+func (t *Tikhonov) Sweep(lambdas []float64) []*Dense {
+	x := make([]*Dense, len(lambdas))
+	for i, lambda := range lambdas {
+		x[i] = t.Solve(lambda)
+	}
+	return x
+}
+
+// Norms returns the residual norm ||a*x - b|| and the solution seminorm ||l*x||
+// of the regularized solution for the given lambda.
+func (t *Tikhonov) Norms(lambda float64) (residual, seminorm float64) {
+	residual = t.delta
+	for j, s := range t.svd.Sigma {
+		f := filter(s, lambda)
+		residual = math.Hypot(residual, (1-f)*t.beta[j])
+		if f != 0 {
+			seminorm = math.Hypot(seminorm, f*t.beta[j]/s)
+		}
+	}
+	return residual, seminorm
+}
+
+// GCV returns the generalized cross-validation function
+//  ||a*x - b||^2 / trace(I - a*a_lambda)^2
+// for the given lambda, where a_lambda is the regularized inverse mapping b
+// to x.
+func (t *Tikhonov) GCV(lambda float64) float64 {
+	res, _ := t.Norms(lambda)
+	dof := float64(t.m - t.q)
+	for _, s := range t.svd.Sigma {
+		dof -= filter(s, lambda)
+	}
+	return res * res / (dof * dof)
+}
+
+// Curvature returns the curvature of the L-curve, the graph of the log of the
+// solution seminorm against the log of the residual norm, at the given lambda.
+// The curvature is computed from the derivatives of the norms with respect to
+// lambda as by Hansen's Regularization Tools, and is positive where the curve
+// is convex.
+func (t *Tikhonov) Curvature(lambda float64) float64 {
+	var eta, rho, phi, psi, dphi, dpsi float64
+	rho = t.delta * t.delta
+	for j, s := range t.svd.Sigma {
+		f := filter(s, lambda)
+		cf := 1 - f
+		beta2 := t.beta[j] * t.beta[j]
+		rho += cf * cf * beta2
+		if s == 0 {
+			continue
+		}
+		xi2 := beta2 / (s * s)
+		eta += f * f * xi2
+
+		f1 := -2 * f * cf / lambda
+		f2 := -f1 * (3 - 4*f) / lambda
+		phi += f * f1 * xi2
+		psi += cf * f1 * beta2
+		dphi += (f1*f1 + f*f2) * xi2
+		dpsi += (-f1*f1 + cf*f2) * beta2
+	}
+	eta, rho = math.Sqrt(eta), math.Sqrt(rho)
+
+	deta := phi / eta
+	drho := -psi / rho
+	ddeta := dphi/eta - deta*deta/eta
+	ddrho := -dpsi/rho - drho*drho/rho
+
+	dlogeta, dlogrho := deta/eta, drho/rho
+	ddlogeta := ddeta/eta - dlogeta*dlogeta
+	ddlogrho := ddrho/rho - dlogrho*dlogrho
+
+	return (dlogrho*ddlogeta - ddlogrho*dlogeta) / math.Pow(dlogrho*dlogrho+dlogeta*dlogeta, 1.5)
+}
+
+// Lambdas returns n regularization parameters spaced logarithmically from the
+// largest singular value of the standard form problem down to the smallest,
+// or to 16*epsilon times the largest if that is greater.
+func (t *Tikhonov) Lambdas(n int) []float64 {
+	sigma := t.svd.Sigma
+	if len(sigma) == 0 || sigma[0] == 0 || n < 1 {
+		return nil
+	}
+	hi := sigma[0]
+	lo := math.Max(sigma[len(sigma)-1], 16*epsilon*hi)
+	lambdas := make([]float64, n)
+	lambdas[0] = hi
+	if n == 1 {
+		return lambdas
+	}
+	ratio := math.Pow(lo/hi, 1/float64(n-1))
+	for i := 1; i < n; i++ {
+		lambdas[i] = lambdas[i-1] * ratio
+	}
+	return lambdas
+}
+
+// MinGCV returns the lambda among lambdas that minimizes GCV. If lambdas is nil,
+// a grid of 200 values from Lambdas is searched.
+func (t *Tikhonov) MinGCV(lambdas []float64) float64 {
+	if lambdas == nil {
+		lambdas = t.Lambdas(tikhonovLambdas)
+	}
+	best, least := math.NaN(), math.Inf(1)
+	for _, lambda := range lambdas {
+		if g := t.GCV(lambda); g < least {
+			best, least = lambda, g
+		}
+	}
+	return best
+}
+
+// LCorner returns the lambda among lambdas at the corner of the L-curve, where
+// Curvature is greatest. If lambdas is nil, a grid of 200 values from Lambdas
+// is searched.
+func (t *Tikhonov) LCorner(lambdas []float64) float64 {
+	if lambdas == nil {
+		lambdas = t.Lambdas(tikhonovLambdas)
+	}
+	best, most := math.NaN(), math.Inf(-1)
+	for _, lambda := range lambdas {
+		if k := t.Curvature(lambda); k > most {
+			best, most = lambda, k
+		}
+	}
+	return best
+}
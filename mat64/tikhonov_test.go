@@ -0,0 +1,160 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+	"math/rand"
+)
+
+// firstDifference returns the (n-1)-by-n first difference operator.
+func firstDifference(n int) *Dense {
+	l := NewDense(n-1, n, nil)
+	for i := 0; i < n-1; i++ {
+		l.Set(i, i, -1)
+		l.Set(i, i+1, 1)
+	}
+	return l
+}
+
+func (s *S) TestTikhonov(c *check.C) {
+	a := NewDense(6, 4, []float64{
+		1, 2, 0, 1,
+		0.5, 1, 1, 0,
+		2, 0, 1, 3,
+		1, 1, 1, 1,
+		0, 3, -1, 2,
+		1, -1, 2, 0,
+	})
+	b := NewDense(6, 1, []float64{1, 2, 0.5, 3, -1, 2})
+	for i, test := range []struct {
+		a, l *Dense
+	}{
+		{a: a},
+		{a: a, l: firstDifference(4)},
+		{a: a, l: NewDense(4, 4, []float64{
+			2, 0, 0, 0,
+			0, 1, 0, 0,
+			0, 0, 0.5, 0,
+			0, 0, 0, 3,
+		})},
+		{a: a, l: NewDense(5, 4, []float64{
+			1, -1, 0, 0,
+			0, 1, -1, 0,
+			0, 0, 1, -1,
+			1, 0, 0, 1,
+			0, 2, 0, 0,
+		})},
+		{a: NewDense(3, 4, []float64{
+			1, 2, 0, 1,
+			0.5, 1, 1, 0,
+			2, 0, 1, 3,
+		})},
+	} {
+		m, n := test.a.Dims()
+		var l Matrix = test.l
+		if test.l == nil {
+			l = nil
+		}
+		bt := &Dense{}
+		bt.Submatrix(b, 0, 0, m, 1)
+		t, err := NewTikhonov(test.a, l, bt)
+		c.Assert(err, check.Equals, nil, check.Commentf("Test %d", i))
+
+		lm := test.l
+		if lm == nil {
+			lm = newIdentity(n)
+		}
+		var at, ata, lt, ltl, atb Dense
+		at.TCopy(test.a)
+		ata.Mul(&at, test.a)
+		lt.TCopy(lm)
+		ltl.Mul(&lt, lm)
+		atb.Mul(&at, bt)
+		for _, lambda := range []float64{0.01, 0.3, 2} {
+			// Compare with the regularized normal equations
+			//  (a'*a + lambda^2*l'*l)*x = a'*b.
+			var reg Dense
+			reg.Scale(lambda*lambda, &ltl)
+			reg.Add(&reg, &ata)
+			want := Solve(&reg, &atb)
+			x := t.Solve(lambda)
+			c.Check(x.EqualsApprox(want, 1e-10), check.Equals, true, check.Commentf("Test %d lambda %v: got %v want %v", i, lambda, x, want))
+
+			var r, lx Dense
+			r.Mul(test.a, x)
+			r.Sub(&r, bt)
+			lx.Mul(lm, x)
+			res, semi := t.Norms(lambda)
+			c.Check(math.Abs(res-r.Norm(0)) < 1e-10, check.Equals, true, check.Commentf("Test %d lambda %v", i, lambda))
+			c.Check(math.Abs(semi-lx.Norm(0)) < 1e-10, check.Equals, true, check.Commentf("Test %d lambda %v", i, lambda))
+
+			// The denominator of the GCV function is the trace of
+			// I - a*inv(a'*a + lambda^2*l'*l)*a'.
+			var infl Dense
+			infl.Mul(test.a, Solve(&reg, &at))
+			gcv := res * res / math.Pow(float64(m)-infl.Trace(), 2)
+			c.Check(math.Abs(t.GCV(lambda)-gcv) < 1e-10*gcv, check.Equals, true, check.Commentf("Test %d lambda %v: got %v want %v", i, lambda, t.GCV(lambda), gcv))
+		}
+	}
+
+	// The null spaces of a and l intersect.
+	_, err := NewTikhonov(NewDense(3, 2, []float64{1, 1, 2, 2, 3, 3}), NewDense(1, 2, []float64{1, 1}), NewDense(3, 1, []float64{1, 2, 3}))
+	c.Check(err, check.Equals, ErrSingular)
+}
+
+func (s *S) TestTikhonovParameterChoice(c *check.C) {
+	// A discretized Gaussian blur with a smooth solution and white
+	// noise on the right hand side.
+	const m, n = 40, 20
+	a := NewDense(m, n, nil)
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			d := (float64(i)+0.5)/m - (float64(j)+0.5)/n
+			a.Set(i, j, math.Exp(-d*d/0.02)/n)
+		}
+	}
+	x := NewDense(n, 1, nil)
+	for j := 0; j < n; j++ {
+		x.Set(j, 0, math.Sin(math.Pi*(float64(j)+0.5)/n))
+	}
+	b := &Dense{}
+	b.Mul(a, x)
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < m; i++ {
+		b.Set(i, 0, b.At(i, 0)+1e-3*rnd.NormFloat64())
+	}
+
+	errorAt := func(t *Tikhonov, lambda float64) float64 {
+		var d Dense
+		d.Sub(t.Solve(lambda), x)
+		return d.Norm(0) / x.Norm(0)
+	}
+
+	for _, l := range []Matrix{nil, firstDifference(n)} {
+		t, err := NewTikhonov(a, l, b)
+		c.Assert(err, check.Equals, nil)
+		lambdas := t.Lambdas(100)
+		c.Check(len(lambdas), check.Equals, 100)
+		for i := 1; i < len(lambdas); i++ {
+			c.Check(lambdas[i] < lambdas[i-1], check.Equals, true)
+		}
+
+		naive := errorAt(t, lambdas[len(lambdas)-1])
+		for _, lambda := range []float64{t.MinGCV(nil), t.LCorner(nil)} {
+			c.Check(lambda > lambdas[len(lambdas)-1] && lambda < lambdas[0], check.Equals, true, check.Commentf("lambda %v", lambda))
+			e := errorAt(t, lambda)
+			c.Check(e < 0.2 && e < naive/100, check.Equals, true, check.Commentf("lambda %v: error %v, unregularized %v", lambda, e, naive))
+		}
+
+		// The GCV choice is the minimizer over the grid.
+		lambda := t.MinGCV(lambdas)
+		for _, v := range lambdas {
+			c.Check(t.GCV(lambda) <= t.GCV(v), check.Equals, true)
+		}
+		c.Check(t.Sweep(lambdas[:3])[2].Equals(t.Solve(lambdas[2])), check.Equals, true)
+	}
+}
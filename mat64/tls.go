@@ -0,0 +1,100 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+)
+
+// TLSResult holds the solution of a total least squares problem.
+type TLSResult struct {
+	// X is the solution, with one column for each column of b.
+	X *Dense
+
+	// Rank is the rank of the approximation to [a b] used to form X.
+	Rank int
+
+	// Generic is false if the generic total least squares solution
+	// of the requested rank does not exist or is not unique, in which
+	// case X is the minimum norm solution of the largest lower rank for
+	// which a unique one exists.
+	Generic bool
+
+	// Sigma holds the singular values of [a b].
+	Sigma []float64
+
+	// Correction is the Frobenius norm of the correction [da db]
+	// taking [a b] to its rank Rank approximation, for which
+	// (a+da)*X = b+db.
+	Correction float64
+}
+
+// TLS returns the total least squares solution of a*x = b for an m-by-n
+// matrix a and an m-by-d matrix b, the solution of the errors-in-variables
+// problem
+//  min ||[da db]||_F subject to (a+da)*x = b+db,
+// computed from the singular value decomposition of [a b] by the method of
+// Van Huffel and Vandewalle, "The Total Least Squares Problem", SIAM, 1991.
+//
+// If rank is zero the classical solution is found from the rank n
+// approximation to [a b]. A rank between 1 and n-1 gives the truncated total
+// least squares solution, which regularizes the problem when a is close to
+// rank deficient. With the right singular vectors of [a b] partitioned as
+//  v = [v11 v12]  n
+//      [v21 v22]  d
+// where v12 and v22 have n+d-rank columns, x = -v12*pinv(v22). If v22 does not
+// have full row rank the generic solution does not exist, and if the singular
+// values sigma[rank-1] and sigma[rank] of [a b] coincide the rank rank
+// approximation, and so x, is not unique. In either case Generic is false and
+// the rank is reduced until sigma[rank-1] > sigma[rank] and v22 has full row
+// rank.
+//
+// TLS will panic with ErrIndexOutOfRange if rank is negative or greater than n.
+func TLS(a, b Matrix, rank int) TLSResult {
+	m, n := a.Dims()
+	bm, d := b.Dims()
+	if bm != m {
+		panic(opError(ErrShape, "TLS", a, b))
+	}
+	if rank < 0 || rank > n {
+		panic(indexError("TLS", a, rank))
+	}
+	if rank == 0 {
+		rank = n
+	}
+
+	c := &Dense{}
+	c.Augment(a, b)
+	f := SVDJobs(c, epsilon, small, SVDNone, SVDFull, false)
+	sigma := make([]float64, n+d)
+	copy(sigma, f.Sigma)
+
+	res := TLSResult{Sigma: f.Sigma, Generic: true}
+	gap := float64(n+d) * epsilon * sigma[0]
+	for k := rank; ; k-- {
+		if k > 0 && sigma[k-1]-sigma[k] <= gap {
+			res.Generic = false
+			continue
+		}
+		v22 := &Dense{}
+		v22.Submatrix(f.V, n, k, d, n+d-k)
+		vf := SVDJobs(v22, epsilon, small, SVDThin, SVDThin, false)
+		if k == 0 || vf.Sigma[d-1] > float64(n+d)*epsilon {
+			v12 := &Dense{}
+			v12.Submatrix(f.V, 0, k, n, n+d-k)
+			res.X = &Dense{}
+			res.X.Mul(v12, vf.PseudoInverse(epsilon))
+			res.X.Scale(-1, res.X)
+			res.Rank = k
+			break
+		}
+		res.Generic = false
+	}
+
+	for _, s := range sigma[res.Rank:] {
+		res.Correction = math.Hypot(res.Correction, s)
+	}
+	return res
+}
@@ -0,0 +1,146 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+)
+
+func (s *S) TestTLS(c *check.C) {
+	for i, test := range []struct {
+		a, b    *Dense
+		rank    int
+		want    int
+		generic bool
+	}{
+		{
+			a: NewDense(5, 2, []float64{
+				1, 1.1,
+				2, 0.9,
+				3, 2.1,
+				4, 1.8,
+				5, 3.2,
+			}),
+			b:       NewDense(5, 1, []float64{2.9, 5.2, 8.1, 9.9, 13.2}),
+			rank:    0,
+			want:    2,
+			generic: true,
+		},
+		{
+			a: NewDense(6, 3, []float64{
+				1, 0.5, 2,
+				0.3, 1, 1.2,
+				2, -1, 0.4,
+				1, 1, 1,
+				-0.5, 2, 0.1,
+				1.5, 0.2, -1,
+			}),
+			b: NewDense(6, 2, []float64{
+				3.4, 1.1,
+				2.6, -0.9,
+				1.3, 3.2,
+				3.1, 0.2,
+				1.7, -2.4,
+				0.8, 1.6,
+			}),
+			rank:    0,
+			want:    3,
+			generic: true,
+		},
+		{
+			// Nearly collinear columns, regularized by truncation.
+			a: NewDense(5, 3, []float64{
+				1, 2, 3.001,
+				2, 1, 3,
+				0, 1, 0.999,
+				1, 1, 2.002,
+				3, -1, 2,
+			}),
+			b:       NewDense(5, 1, []float64{6, 6.1, 2, 4, 3.9}),
+			rank:    2,
+			want:    2,
+			generic: true,
+		},
+		{
+			// [a b] has a two dimensional null space, so the rank 3
+			// approximation is not unique and the minimum norm
+			// solution is that of rank 2.
+			a: NewDense(2, 3, []float64{
+				1, 2, 0,
+				0, 1, 3,
+			}),
+			b:       NewDense(2, 1, []float64{1, 2}),
+			rank:    0,
+			want:    2,
+			generic: false,
+		},
+	} {
+		m, n := test.a.Dims()
+		_, d := test.b.Dims()
+		res := TLS(test.a, test.b, test.rank)
+		c.Check(res.Generic, check.Equals, test.generic, check.Commentf("Test %d", i))
+		k := test.want
+		c.Check(res.Rank, check.Equals, k, check.Commentf("Test %d", i))
+
+		// The solution is consistent with the rank k approximation
+		// to [a b] whose distance from [a b] is the correction.
+		var ab Dense
+		ab.Augment(test.a, test.b)
+		abk := SVDJobs(DenseCopyOf(&ab), epsilon, small, SVDThin, SVDThin, false).Truncated(min(k, m))
+		ak, bk := &Dense{}, &Dense{}
+		ak.Submatrix(abk, 0, 0, m, n)
+		bk.Submatrix(abk, 0, n, m, d)
+		var ax Dense
+		ax.Mul(ak, res.X)
+		c.Check(ax.EqualsApprox(bk, 1e-12), check.Equals, true, check.Commentf("Test %d: %v %v", i, &ax, bk))
+
+		var diff Dense
+		diff.Sub(&ab, abk)
+		c.Check(math.Abs(diff.Norm(0)-res.Correction) < 1e-12, check.Equals, true, check.Commentf("Test %d", i))
+
+		if d == 1 && k == n && len(res.Sigma) > n {
+			// For a single right hand side the classical solution
+			// satisfies (a'*a - sigma^2*I)*x = a'*b, with sigma the
+			// smallest singular value of [a b].
+			var at, ata, atb Dense
+			at.TCopy(test.a)
+			ata.Mul(&at, test.a)
+			atb.Mul(&at, test.b)
+			s2 := res.Sigma[n] * res.Sigma[n]
+			for j := 0; j < n; j++ {
+				ata.Set(j, j, ata.At(j, j)-s2)
+			}
+			want := Solve(&ata, &atb)
+			c.Check(res.X.EqualsApprox(want, 1e-10), check.Equals, true, check.Commentf("Test %d: got %v want %v", i, res.X, want))
+		}
+	}
+
+	// Consistent data are fitted exactly.
+	a := NewDense(4, 2, []float64{1, 2, 3, 4, 5, 6, 7, 9})
+	x := NewDense(2, 1, []float64{0.5, -2})
+	var b Dense
+	b.Mul(a, x)
+	res := TLS(a, &b, 0)
+	c.Check(res.X.EqualsApprox(x, 1e-12), check.Equals, true)
+	c.Check(res.Correction < 1e-12, check.Equals, true)
+
+	// The direction of least variation lies wholly in a, so no generic
+	// solution exists.
+	res = TLS(NewDense(3, 2, []float64{2, 0, 0, 0, 0, 0}), NewDense(3, 1, []float64{0, 0, 1}), 0)
+	c.Check(res.Generic, check.Equals, false)
+	c.Check(res.Rank, check.Equals, 1)
+	c.Check(res.X.EqualsApprox(NewDense(2, 1, nil), 1e-15), check.Equals, true)
+
+	// All singular values of [a b] coincide, so no rank gives a unique
+	// approximation and the minimum norm solution is zero.
+	res = TLS(NewDense(3, 2, []float64{1, 0, 0, 1, 0, 0}), NewDense(3, 1, []float64{0, 0, 1}), 0)
+	c.Check(res.Generic, check.Equals, false)
+	c.Check(res.Rank, check.Equals, 0)
+	c.Check(res.X.EqualsApprox(NewDense(2, 1, nil), 1e-15), check.Equals, true)
+
+	c.Check(func() { TLS(newIdentity(2), NewDense(2, 1, nil), 3) }, check.PanicMatches, ".*index out of range.*")
+	c.Check(func() { TLS(newIdentity(2), NewDense(2, 1, nil), -1) }, check.PanicMatches, ".*index out of range.*")
+}
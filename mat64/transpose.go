@@ -0,0 +1,72 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"github.com/gonum/blas"
+)
+
+var (
+	transpose Transpose
+
+	_ Matrix     = transpose
+	_ Transposer = transpose
+)
+
+// Transpose is a lazy transposed view of a Matrix. Element (r, c) of the view
+// is element (c, r) of the wrapped Matrix, so changes to the wrapped Matrix are
+// reflected in the view. Dense.Mul, Vec.Mul and Solve recognize a Transpose of
+// a RawMatrixer and pass it to BLAS or the factorizations as transposed rather
+// than copying it.
+type Transpose struct {
+	Matrix Matrix
+}
+
+// At returns the value of the element at row r and column c of the transposed
+// matrix, that is, the element at row c and column r of the wrapped Matrix.
+func (t Transpose) At(r, c int) float64 {
+	return t.Matrix.At(c, r)
+}
+
+// Dims returns the dimensions of the transposed matrix.
+func (t Transpose) Dims() (r, c int) {
+	c, r = t.Matrix.Dims()
+	return r, c
+}
+
+// T returns the wrapped Matrix, undoing the transpose.
+func (t Transpose) T() Matrix {
+	return t.Matrix
+}
+
+// T returns a transposed view of the receiver. No data is copied.
+func (m *Dense) T() Matrix {
+	return Transpose{m}
+}
+
+// untranspose returns the matrix underlying a, unwrapping a Transpose, and the
+// corresponding BLAS transpose flag.
+func untranspose(a Matrix) (Matrix, blas.Transpose) {
+	if t, ok := a.(Transpose); ok {
+		return t.Matrix, blas.Trans
+	}
+	return a, blas.NoTrans
+}
+
+// solveColumns overwrites each column of b with the result of solve applied
+// to it.
+func solveColumns(b *Dense, solve func([]float64)) {
+	m, n := b.Dims()
+	col := make([]float64, m)
+	for j := 0; j < n; j++ {
+		for i := range col {
+			col[i] = b.At(i, j)
+		}
+		solve(col)
+		for i, v := range col {
+			b.Set(i, j, v)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+)
+
+func (s *S) TestTransposeView(c *check.C) {
+	a := NewDense(flatten([][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+	}))
+	t := a.T()
+	r, cols := t.Dims()
+	c.Check(r, check.Equals, 3)
+	c.Check(cols, check.Equals, 2)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 2; j++ {
+			c.Check(t.At(i, j), check.Equals, a.At(j, i))
+		}
+	}
+
+	// The view reflects changes to the original.
+	a.Set(1, 2, 10)
+	c.Check(t.At(2, 1), check.Equals, 10.)
+
+	c.Check(t.(Transposer).T(), check.Equals, Matrix(a))
+}
+
+func (s *S) TestTransposeMul(c *check.C) {
+	for i, test := range []struct {
+		a, b [][]float64
+	}{
+		{
+			a: [][]float64{{1, 2}, {3, 4}},
+			b: [][]float64{{5, -6}, {7, 8}},
+		},
+		{
+			a: [][]float64{{1, 2, 3}, {4, 5, 6}},
+			b: [][]float64{{-1, 0.5, 2}, {3, 1, -4}},
+		},
+		{
+			a: [][]float64{{1, 2}, {3, 4}, {5, 6}},
+			b: [][]float64{{0, 1}, {2, -3}, {4, 5}, {-6, 7}},
+		},
+	} {
+		a := NewDense(flatten(test.a))
+		b := NewDense(flatten(test.b))
+		at, bt := &Dense{}, &Dense{}
+		at.TCopy(a)
+		bt.TCopy(b)
+
+		for _, op := range []struct {
+			x, y         Matrix
+			xCopy, yCopy Matrix
+		}{
+			{x: a.T(), y: a, xCopy: at, yCopy: a},
+			{x: a, y: a.T(), xCopy: a, yCopy: at},
+			{x: b, y: a.T(), xCopy: b, yCopy: at},
+			{x: a.T(), y: b.T(), xCopy: at, yCopy: bt},
+		} {
+			_, xc := op.x.Dims()
+			if yr, _ := op.y.Dims(); xc != yr {
+				continue
+			}
+			var got, want Dense
+			got.Mul(op.x, op.y)
+			want.Mul(op.xCopy, op.yCopy)
+			c.Check(got.EqualsApprox(&want, 1e-14), check.Equals, true, check.Commentf("Test %d", i))
+		}
+
+		// The receiver may be the matrix underlying a transposed operand.
+		ata := &Dense{}
+		ata.Mul(at, a)
+		a.Mul(a.T(), a)
+		c.Check(a.EqualsApprox(ata, 1e-14), check.Equals, true, check.Commentf("Test %d", i))
+	}
+}
+
+func (s *S) TestTransposeVecMul(c *check.C) {
+	a := NewDense(flatten([][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+	}))
+	x := Vec{1, -1}
+	var got Vec
+	got.Mul(a.T(), &x)
+	c.Check(got, check.DeepEquals, Vec{-3, -3, -3})
+}
+
+func (s *S) TestTransposeSolve(c *check.C) {
+	for i, test := range []struct {
+		a, b [][]float64
+	}{
+		{
+			a: [][]float64{
+				{4, 1, 2},
+				{-1, 3, 0.5},
+				{2, -2, 5},
+			},
+			b: [][]float64{{1, 2}, {3, 4}, {5, 6}},
+		},
+		{
+			// a' is wide, giving the minimum norm solution.
+			a: [][]float64{
+				{1, 2},
+				{3, -4},
+				{5, 6},
+				{-7, 8},
+			},
+			b: [][]float64{{1}, {2}},
+		},
+		{
+			// a' is tall, giving the least squares solution.
+			a: [][]float64{
+				{1, 2, 3, 4},
+				{-5, 6, 7, 8},
+			},
+			b: [][]float64{{1}, {-2}, {3}, {4}},
+		},
+	} {
+		a := NewDense(flatten(test.a))
+		b := NewDense(flatten(test.b))
+		at := &Dense{}
+		at.TCopy(a)
+
+		got := Solve(a.T(), b)
+		want := Solve(at, b)
+		c.Check(got.EqualsApprox(want, 1e-12), check.Equals, true, check.Commentf("Test %d", i))
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+// The Try functions and methods are error-returning forms of operations that
+// panic with an Error or *OpError on invalid arguments such as mismatched shapes
+// or singular matrices. Each performs the operation as its panicking counterpart
+// does and returns the error instead of panicking. Other panics, which indicate
+// a bug or corrupted matrix, are not recovered.
+
+// TryAdd performs m.Add(a, b), returning any Error.
+func (m *Dense) TryAdd(a, b Matrix) error {
+	return Maybe(func() { m.Add(a, b) })
+}
+
+// TrySub performs m.Sub(a, b), returning any Error.
+func (m *Dense) TrySub(a, b Matrix) error {
+	return Maybe(func() { m.Sub(a, b) })
+}
+
+// TryMulElem performs m.MulElem(a, b), returning any Error.
+func (m *Dense) TryMulElem(a, b Matrix) error {
+	return Maybe(func() { m.MulElem(a, b) })
+}
+
+// TryMul performs m.Mul(a, b), returning any Error.
+func (m *Dense) TryMul(a, b Matrix) error {
+	return Maybe(func() { m.Mul(a, b) })
+}
+
+// TryDot returns m.Dot(b) and any Error.
+func (m *Dense) TryDot(b Matrix) (float64, error) {
+	return MaybeFloat(func() float64 { return m.Dot(b) })
+}
+
+// TryScale performs m.Scale(f, a), returning any Error.
+func (m *Dense) TryScale(f float64, a Matrix) error {
+	return Maybe(func() { m.Scale(f, a) })
+}
+
+// TryApply performs m.Apply(f, a), returning any Error.
+func (m *Dense) TryApply(f ApplyFunc, a Matrix) error {
+	return Maybe(func() { m.Apply(f, a) })
+}
+
+// TryTCopy performs m.TCopy(a), returning any Error.
+func (m *Dense) TryTCopy(a Matrix) error {
+	return Maybe(func() { m.TCopy(a) })
+}
+
+// TryStack performs m.Stack(a, b), returning any Error.
+func (m *Dense) TryStack(a, b Matrix) error {
+	return Maybe(func() { m.Stack(a, b) })
+}
+
+// TryAugment performs m.Augment(a, b), returning any Error.
+func (m *Dense) TryAugment(a, b Matrix) error {
+	return Maybe(func() { m.Augment(a, b) })
+}
+
+// TryView performs m.View(a, i, j, r, c), returning any Error.
+func (m *Dense) TryView(a Matrix, i, j, r, c int) error {
+	return Maybe(func() { m.View(a, i, j, r, c) })
+}
+
+// TrySubmatrix performs m.Submatrix(a, i, j, r, c), returning any Error.
+func (m *Dense) TrySubmatrix(a Matrix, i, j, r, c int) error {
+	return Maybe(func() { m.Submatrix(a, i, j, r, c) })
+}
+
+// TryNorm returns m.Norm(ord) and any Error.
+func (m *Dense) TryNorm(ord float64) (float64, error) {
+	return MaybeFloat(func() float64 { return m.Norm(ord) })
+}
+
+// TryNewDense returns NewDense(r, c, mat) and any Error.
+func TryNewDense(r, c int, mat []float64) (m *Dense, err error) {
+	err = Maybe(func() { m = NewDense(r, c, mat) })
+	return m, err
+}
+
+// TrySolve returns Solve(a, b) and any Error, including ErrSingular for a
+// singular square a and ErrRankDeficient for a rank deficient non-square a.
+func TrySolve(a, b Matrix) (x *Dense, err error) {
+	err = Maybe(func() { x = Solve(a, b) })
+	return x, err
+}
+
+// TrySolveEquilibrated returns SolveEquilibrated(a, b) and any Error, as for
+// TrySolve.
+func TrySolveEquilibrated(a, b Matrix) (x *Dense, err error) {
+	err = Maybe(func() { x = SolveEquilibrated(a, b) })
+	return x, err
+}
+
+// TryDet returns Det(a) and any Error.
+func TryDet(a Matrix) (float64, error) {
+	return MaybeFloat(func() float64 { return Det(a) })
+}
+
+// TryQR returns QR(a) and any Error.
+func TryQR(a *Dense) (f QRFactor, err error) {
+	err = Maybe(func() { f = QR(a) })
+	return f, err
+}
+
+// TrySolve returns f.Solve(b) and any Error, including ErrSingular if a is
+// singular.
+func (f LUFactors) TrySolve(b *Dense) (x *Dense, err error) {
+	err = Maybe(func() { x = f.Solve(b) })
+	return x, err
+}
+
+// TrySolve returns f.Solve(b) and any Error, including ErrNotPosDef if a is
+// not symmetric positive definite.
+func (f CholeskyFactor) TrySolve(b *Dense) (x *Dense, err error) {
+	err = Maybe(func() { x = f.Solve(b) })
+	return x, err
+}
+
+// TrySolve returns f.Solve(b) and any Error, including ErrRankDeficient if a
+// does not have full rank.
+func (f QRFactor) TrySolve(b *Dense) (x *Dense, err error) {
+	err = Maybe(func() { x = f.Solve(b) })
+	return x, err
+}
+
+// TrySolve returns f.Solve(b) and any Error, including ErrRankDeficient if a
+// does not have full rank.
+func (f LQFactor) TrySolve(b *Dense) (x *Dense, err error) {
+	err = Maybe(func() { x = f.Solve(b) })
+	return x, err
+}
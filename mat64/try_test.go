@@ -0,0 +1,114 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"errors"
+
+	check "launchpad.net/gocheck"
+)
+
+func (s *S) TestTry(c *check.C) {
+	a := NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})
+	b := NewDense(3, 2, []float64{1, 0, 0, 1, 1, 1})
+
+	var m Dense
+	c.Check(m.TryMul(a, b), check.Equals, nil)
+	c.Check(m.Equals(NewDense(2, 2, []float64{4, 5, 10, 11})), check.Equals, true)
+	c.Check(errors.Is(m.TryMul(a, a), ErrShape), check.Equals, true)
+	c.Check(errors.Is(m.TryAdd(a, b), ErrShape), check.Equals, true)
+	c.Check(errors.Is(m.TrySub(a, b), ErrShape), check.Equals, true)
+	c.Check(errors.Is(m.TryMulElem(a, b), ErrShape), check.Equals, true)
+	c.Check(errors.Is(m.TryStack(a, b), ErrShape), check.Equals, true)
+	c.Check(errors.Is(m.TryAugment(a, b), ErrShape), check.Equals, true)
+	_, err := m.TryNorm(3)
+	c.Check(errors.Is(err, ErrNormOrder), check.Equals, true)
+	_, err = a.TryDot(b)
+	c.Check(errors.Is(err, ErrShape), check.Equals, true)
+	_, err = TryNewDense(2, 2, []float64{1, 2, 3})
+	c.Check(errors.Is(err, ErrShape), check.Equals, true)
+	_, err = TryDet(a)
+	c.Check(errors.Is(err, ErrSquare), check.Equals, true)
+	_, err = TryQR(DenseCopyOf(a))
+	c.Check(errors.Is(err, ErrShape), check.Equals, true)
+
+	sing := NewDense(2, 2, []float64{1, 2, 2, 4})
+	_, err = TrySolve(sing, NewDense(2, 1, []float64{1, 1}))
+	c.Check(errors.Is(err, ErrSingular), check.Equals, true)
+	_, err = TrySolve(NewDense(3, 2, []float64{1, 2, 0, 0, 0, 0}), NewDense(3, 1, nil))
+	c.Check(errors.Is(err, ErrRankDeficient), check.Equals, true)
+	_, err = TrySolve(NewDense(2, 3, []float64{1, 0, 0, 2, 0, 0}), NewDense(2, 1, nil))
+	c.Check(errors.Is(err, ErrRankDeficient), check.Equals, true)
+	x, err := TrySolve(NewDense(2, 2, []float64{2, 0, 0, 4}), NewDense(2, 1, []float64{2, 2}))
+	c.Check(err, check.Equals, nil)
+	c.Check(x.Equals(NewDense(2, 1, []float64{1, 0.5})), check.Equals, true)
+}
+
+func (s *S) TestTryFactorSolve(c *check.C) {
+	spd := NewDense(2, 2, []float64{4, 1, 1, 3})
+	notSPD := NewDense(2, 2, []float64{1, 2, 2, 1})
+	rhs := func() *Dense { return NewDense(2, 1, []float64{1, 2}) }
+
+	_, err := LU(NewDense(2, 2, []float64{1, 2, 2, 4})).TrySolve(rhs())
+	c.Check(errors.Is(err, ErrSingular), check.Equals, true)
+	_, err = LU(DenseCopyOf(spd)).TrySolve(NewDense(3, 1, nil))
+	c.Check(errors.Is(err, ErrShape), check.Equals, true)
+
+	_, err = Cholesky(DenseCopyOf(notSPD)).TrySolve(rhs())
+	c.Check(errors.Is(err, ErrNotPosDef), check.Equals, true)
+	_, err = Cholesky(DenseCopyOf(spd)).TrySolve(NewDense(3, 1, nil))
+	c.Check(errors.Is(err, ErrShape), check.Equals, true)
+	x, err := Cholesky(DenseCopyOf(spd)).TrySolve(rhs())
+	c.Check(err, check.Equals, nil)
+	var ax Dense
+	ax.Mul(spd, x)
+	c.Check(ax.EqualsApprox(rhs(), 1e-14), check.Equals, true)
+
+	_, err = QR(NewDense(2, 2, []float64{1, 2, 2, 4})).TrySolve(rhs())
+	c.Check(errors.Is(err, ErrRankDeficient), check.Equals, true)
+	_, err = LQ(NewDense(2, 2, []float64{1, 0, 2, 0})).TrySolve(rhs())
+	c.Check(errors.Is(err, ErrRankDeficient), check.Equals, true)
+
+	// Factor solves panic with an Error, so Maybe recovers them.
+	err = Maybe(func() { QR(NewDense(2, 2, []float64{1, 2, 2, 4})).Solve(rhs()) })
+	c.Check(errors.Is(err, ErrRankDeficient), check.Equals, true)
+	err = Maybe(func() { Cholesky(DenseCopyOf(notSPD)).Solve(rhs()) })
+	c.Check(errors.Is(err, ErrNotPosDef), check.Equals, true)
+}
+
+func (s *S) TestOpError(c *check.C) {
+	a := NewDense(2, 3, nil)
+	var m Dense
+	err := Maybe(func() { m.Mul(a, a) })
+	c.Check(errors.Is(err, ErrShape), check.Equals, true)
+	c.Check(errors.Is(err, ErrSquare), check.Equals, false)
+	var e *OpError
+	c.Assert(errors.As(err, &e), check.Equals, true)
+	c.Check(e.Op, check.Equals, "Dense.Mul")
+	c.Check(e.Dims, check.DeepEquals, [][2]int{{0, 0}, {2, 3}, {2, 3}})
+	c.Check(e.Error(), check.Equals, "mat64: dimension mismatch: Dense.Mul(0x0, 2x3, 2x3)")
+
+	_, err = MaybeFloat(func() float64 { return a.Col(nil, 4)[0] })
+	c.Check(errors.Is(err, ErrIndexOutOfRange), check.Equals, true)
+	c.Assert(errors.As(err, &e), check.Equals, true)
+	c.Check(e.Index, check.DeepEquals, []int{4})
+	c.Check(e.Error(), check.Equals, "mat64: index out of range: Dense.Col(2x3) at index [4]")
+
+	_, err = LU(NewDense(2, 2, []float64{1, 2, 2, 4})).TrySolve(NewDense(2, 1, nil))
+	c.Assert(errors.As(err, &e), check.Equals, true)
+	c.Check(e.Err, check.Equals, ErrSingular)
+	c.Check(e.Op, check.Equals, "LUFactors.Solve")
+
+	err = Maybe(func() { Sylvester(NewDense(2, 2, nil), NewDense(3, 3, nil), NewDense(3, 2, nil)) })
+	c.Assert(errors.As(err, &e), check.Equals, true)
+	c.Check(e.Error(), check.Equals, "mat64: dimension mismatch: Sylvester(2x2, 3x3, 3x2)")
+	err = Maybe(func() { DiscreteLyapunov(NewDense(2, 3, nil), NewDense(2, 2, nil)) })
+	c.Assert(errors.As(err, &e), check.Equals, true)
+	c.Check(e.Err, check.Equals, ErrSquare)
+	c.Check(e.Op, check.Equals, "DiscreteLyapunov")
+
+	// Panics that are not matrix errors are not recovered.
+	c.Check(func() { Maybe(func() { panic("other") }) }, check.PanicMatches, "other")
+}
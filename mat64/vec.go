@@ -5,7 +5,7 @@
 package mat64
 
 import (
-	"github.com/gonum/blas"
+	"math"
 )
 
 var (
@@ -14,78 +14,478 @@ var (
 	_ Matrix  = vector
 	_ Mutable = vector
 
-	// _ Cloner      = vector
-	// _ Viewer      = vector
-	// _ Subvectorer = vector
+	_ Cloner      = vector
+	_ Viewer      = vector
+	_ Subvectorer = vector
 
-	// _ Adder     = vector
-	// _ Suber     = vector
-	_ Muler = vector
-	// _ Dotter    = vector
-	// _ ElemMuler = vector
+	_ Adder     = vector
+	_ Suber     = vector
+	_ Muler     = vector
+	_ Dotter    = vector
+	_ ElemMuler = vector
 
-	// _ Scaler  = vector
-	// _ Applyer = vector
+	_ Scaler  = vector
+	_ Applyer = vector
 
-	// _ Normer = vector
-	// _ Sumer  = vector
+	_ Normer = vector
+	_ Sumer  = vector
 
-	// _ Stacker   = vector
+	_ Stacker = vector
 	// _ Augmenter = vector
 
-	// _ Equaler       = vector
-	// _ ApproxEqualer = vector
+	_ Equaler       = vector
+	_ ApproxEqualer = vector
 
-	// _ RawMatrixLoader = vector
-	// _ RawMatrixer     = vector
+	_ RawMatrixLoader = vector
+	_ RawMatrixer     = vector
 )
 
+// Vec is a column vector. Operands of Vec methods may be any column vector
+// Matrix; a Vec, a VecView, a single column Dense view or a Transpose of a
+// single row Dense view are used in place with the appropriate BLAS increment,
+// so rows and columns of a Dense can take part without being copied.
 type Vec []float64
 
 func (m Vec) At(r, c int) float64 {
-	if c != 0 || r < 0 || r >= len(m) {
-		panic(ErrIndexOutOfRange)
+	if checkBounds && (c != 0 || r < 0 || r >= len(m)) {
+		panic(indexError("Vec.At", m, r, c))
 	}
 	return m[r]
 }
 
 func (m Vec) Set(r, c int, v float64) {
-	if c != 0 || r < 0 || r >= len(m) {
-		panic(ErrIndexOutOfRange)
+	if checkBounds && (c != 0 || r < 0 || r >= len(m)) {
+		panic(indexError("Vec.Set", m, r, c))
 	}
 	m[r] = v
 }
 
 func (m Vec) Dims() (r, c int) { return len(m), 1 }
 
+// vectorData returns the elements of the column vector a as BLAS vector data
+// with increment inc, without copying. ok is false if a is not backed by a
+// RawMatrixer column or a transposed RawMatrixer row.
+func vectorData(a Matrix) (data []float64, inc int, ok bool) {
+	switch a := a.(type) {
+	case Transpose:
+		if raw, ok := a.Matrix.(RawMatrixer); ok {
+			amat := raw.RawMatrix()
+			if amat.Rows == 1 {
+				return amat.Data[:amat.Cols], 1, true
+			}
+		}
+	case RawMatrixer:
+		amat := a.RawMatrix()
+		if amat.Cols == 1 {
+			if amat.Rows == 0 {
+				return nil, 1, true
+			}
+			return amat.Data[:(amat.Rows-1)*amat.Stride+1], amat.Stride, true
+		}
+	}
+	return nil, 0, false
+}
+
+// vectorOf returns the elements of the column vector a as BLAS vector data
+// with increment inc, copying them only if vectorData cannot use a in place.
+func vectorOf(a Matrix) (data []float64, inc int) {
+	if data, inc, ok := vectorData(a); ok {
+		return data, inc
+	}
+	r, _ := a.Dims()
+	data = make([]float64, r)
+	for i := range data {
+		data[i] = a.At(i, 0)
+	}
+	return data, 1
+}
+
+// sameVector returns whether the BLAS vectors x and y, of a common length,
+// start at the same element and have the same increment, so that they hold
+// the same elements.
+func sameVector(x []float64, incX int, y []float64, incY int) bool {
+	return len(x) != 0 && len(y) != 0 && &x[0] == &y[0] && incX == incY
+}
+
+// overlaps returns whether the elements of the BLAS vector x with increment
+// incX and nx elements may share storage with those of y. Two slices share a
+// backing array exactly when their capacities end at the same element, and
+// the offset of one from the other is then the difference of their
+// capacities. Interleaved vectors are reported as overlapping.
+func overlaps(x []float64, incX, nx int, y []float64, incY, ny int) bool {
+	if nx == 0 || ny == 0 || cap(x) == 0 || cap(y) == 0 {
+		return false
+	}
+	xc, yc := x[:cap(x)], y[:cap(y)]
+	if &xc[len(xc)-1] != &yc[len(yc)-1] {
+		return false
+	}
+	off := cap(y) - cap(x)
+	return off <= (ny-1)*incY && 0 <= off+(nx-1)*incX
+}
+
+// unalias returns the n-vector x with increment incX, copied to new storage
+// if it overlaps the vector w that is about to be written without being the
+// same vector, so that writing w cannot change x before it is read.
+func unalias(w []float64, incW int, x []float64, incX, n int) ([]float64, int) {
+	if sameVector(w, incW, x, incX) || !overlaps(w, incW, n, x, incX, n) {
+		return x, incX
+	}
+	t := make([]float64, n)
+	blasEngine.Dcopy(n, x, incX, t, 1)
+	return t, 1
+}
+
+// reuseAs checks that the operands are column vectors with the same number of
+// rows and prepares the receiver to hold the result, allocating it if it is
+// empty. The number of rows is returned.
+func (m *Vec) reuseAs(op string, a Matrix, others ...Matrix) int {
+	r, c := a.Dims()
+	if c != 1 {
+		panic(opError(ErrShape, op, append([]Matrix{m, a}, others...)...))
+	}
+	for _, b := range others {
+		if br, bc := b.Dims(); br != r || bc != 1 {
+			panic(opError(ErrShape, op, append([]Matrix{m, a}, others...)...))
+		}
+	}
+	if len(*m) == 0 {
+		*m = use(*m, r)
+	} else if len(*m) != r {
+		panic(opError(ErrShape, op, append([]Matrix{m, a}, others...)...))
+	}
+	return r
+}
+
+// Clone makes a copy of the column vector a into the receiver.
+func (m *Vec) Clone(a Matrix) {
+	if _, c := a.Dims(); c != 1 {
+		panic(opError(ErrShape, "Vec.Clone", m, a))
+	}
+	ad, ai := vectorOf(a)
+	r, _ := a.Dims()
+	w := make(Vec, r)
+	if r != 0 {
+		blasEngine.Dcopy(r, ad, ai, w, 1)
+	}
+	*m = w
+}
+
+// View sets the receiver to the r elements of column j of a starting at row i.
+// The column must be stored contiguously, as for a Vec, a single column Dense
+// with unit stride or a Transpose of a Dense, so that changes to the receiver
+// are reflected in a. View panics with ErrIllegalStride for other columns.
+func (m *Vec) View(a Matrix, i, j, r, c int) {
+	ar, ac := a.Dims()
+	if c != 1 {
+		panic(opError(ErrShape, "Vec.View", m, a))
+	}
+	if i < 0 || j < 0 || r < 0 || i+r > ar || j >= ac {
+		panic(indexError("Vec.View", a, i, j))
+	}
+	switch a := a.(type) {
+	case Transpose:
+		if raw, ok := a.Matrix.(RawMatrixer); ok {
+			amat := raw.RawMatrix()
+			off := j * amat.Stride
+			*m = amat.Data[off+i : off+i+r]
+			return
+		}
+	case RawMatrixer:
+		amat := a.RawMatrix()
+		if amat.Stride == 1 || r <= 1 {
+			off := i*amat.Stride + j
+			*m = amat.Data[off : off+r]
+			return
+		}
+	}
+	panic(opError(ErrIllegalStride, "Vec.View", m, a))
+}
+
+// Subvector sets the receiver to a view of the n elements of the column vector a
+// starting at element i. The elements must be stored contiguously, as for View.
+func (m *Vec) Subvector(a Matrix, i, n int) {
+	m.View(a, i, 0, n, 1)
+}
+
+// Add performs element-wise addition of a and b, placing the result in the
+// receiver.
+func (m *Vec) Add(a, b Matrix) {
+	n := m.reuseAs("Vec.Add", a, b)
+	addTo(*m, 1, n, a, b)
+}
+
+// addTo places the element-wise sum of the n-vectors a and b in the BLAS vector
+// w with increment wi.
+func addTo(w []float64, wi, n int, a, b Matrix) {
+	if n == 0 {
+		return
+	}
+	ad, ai := vectorOf(a)
+	bd, bi := vectorOf(b)
+	ad, ai = unalias(w, wi, ad, ai, n)
+	bd, bi = unalias(w, wi, bd, bi, n)
+	if sameVector(w, wi, bd, bi) {
+		ad, ai, bd, bi = bd, bi, ad, ai
+	}
+	if !sameVector(w, wi, ad, ai) {
+		blasEngine.Dcopy(n, ad, ai, w, wi)
+	}
+	blasEngine.Daxpy(n, 1, bd, bi, w, wi)
+}
+
+// Sub performs element-wise subtraction of b from a, placing the result in the
+// receiver.
+func (m *Vec) Sub(a, b Matrix) {
+	n := m.reuseAs("Vec.Sub", a, b)
+	subTo(*m, 1, n, a, b)
+}
+
+// subTo places the element-wise difference of the n-vectors a and b in the
+// BLAS vector w with increment wi.
+func subTo(w []float64, wi, n int, a, b Matrix) {
+	if n == 0 {
+		return
+	}
+	ad, ai := vectorOf(a)
+	bd, bi := vectorOf(b)
+	ad, ai = unalias(w, wi, ad, ai, n)
+	bd, bi = unalias(w, wi, bd, bi, n)
+	if sameVector(w, wi, bd, bi) && !sameVector(w, wi, ad, ai) {
+		blasEngine.Dscal(n, -1, w, wi)
+		blasEngine.Daxpy(n, 1, ad, ai, w, wi)
+		return
+	}
+	if !sameVector(w, wi, ad, ai) {
+		blasEngine.Dcopy(n, ad, ai, w, wi)
+	}
+	blasEngine.Daxpy(n, -1, bd, bi, w, wi)
+}
+
+// MulElem performs element-wise multiplication of a and b, placing the result
+// in the receiver.
+func (m *Vec) MulElem(a, b Matrix) {
+	n := m.reuseAs("Vec.MulElem", a, b)
+	ad, ai := vectorOf(a)
+	bd, bi := vectorOf(b)
+	ad, ai = unalias(*m, 1, ad, ai, n)
+	bd, bi = unalias(*m, 1, bd, bi, n)
+	for i := range *m {
+		(*m)[i] = ad[i*ai] * bd[i*bi]
+	}
+}
+
+// Dot returns the dot product of the receiver and the column vector b.
+func (m Vec) Dot(b Matrix) float64 {
+	if br, bc := b.Dims(); br != len(m) || bc != 1 {
+		panic(opError(ErrShape, "Vec.Dot", m, b))
+	}
+	if len(m) == 0 {
+		return 0
+	}
+	bd, bi := vectorOf(b)
+	return blasEngine.Ddot(len(m), m, 1, bd, bi)
+}
+
+// Scale multiplies the elements of a by f, placing the result in the receiver.
+func (m *Vec) Scale(f float64, a Matrix) {
+	n := m.reuseAs("Vec.Scale", a)
+	scaleTo(*m, 1, n, f, a)
+}
+
+// scaleTo places the n-vector a multiplied by f in the BLAS vector w with
+// increment wi.
+func scaleTo(w []float64, wi, n int, f float64, a Matrix) {
+	if n == 0 {
+		return
+	}
+	ad, ai := vectorOf(a)
+	ad, ai = unalias(w, wi, ad, ai, n)
+	if !sameVector(w, wi, ad, ai) {
+		blasEngine.Dcopy(n, ad, ai, w, wi)
+	}
+	blasEngine.Dscal(n, f, w, wi)
+}
+
+// Apply applies the function f to each of the elements of a, placing the
+// resulting vector in the receiver.
+func (m *Vec) Apply(f ApplyFunc, a Matrix) {
+	n := m.reuseAs("Vec.Apply", a)
+	ad, ai := vectorOf(a)
+	ad, ai = unalias(*m, 1, ad, ai, n)
+	for i := range *m {
+		(*m)[i] = f(i, 0, ad[i*ai])
+	}
+}
+
+// Norm returns the specified norm of the receiver. For a column vector the
+// 1 and -1 norms are the sum of the absolute values of the elements, the Inf
+// and -Inf norms are the largest and smallest absolute value, and the 0, 2 and
+// -2 norms are the Euclidean norm, since that is the only singular value of a
+// column vector. The norms of an empty vector are zero.
+func (m Vec) Norm(o float64) float64 {
+	return vectorNorm("Vec.Norm", m, 1, len(m), o)
+}
+
+// vectorNorm returns the specified norm of the BLAS vector x with increment inc
+// and n elements, as for Vec.Norm. An invalid order is reported as an error in
+// the operation op.
+func vectorNorm(op string, x []float64, inc, n int, o float64) float64 {
+	switch {
+	case o == 1, o == -1:
+		if n == 0 {
+			return 0
+		}
+		return blasEngine.Dasum(n, x, inc)
+	case math.IsInf(o, +1):
+		if n == 0 {
+			return 0
+		}
+		return math.Abs(x[blasEngine.Idamax(n, x, inc)*inc])
+	case math.IsInf(o, -1):
+		if n == 0 {
+			return 0
+		}
+		v := math.MaxFloat64
+		for i := 0; i < n; i++ {
+			v = math.Min(v, math.Abs(x[i*inc]))
+		}
+		return v
+	case o == 0, o == 2, o == -2:
+		if n == 0 {
+			return 0
+		}
+		return blasEngine.Dnrm2(n, x, inc)
+	default:
+		panic(dimsError(ErrNormOrder, op, [2]int{n, 1}))
+	}
+}
+
+// Sum returns the sum of the elements of the receiver.
+func (m Vec) Sum() float64 {
+	var s float64
+	for _, v := range m {
+		s += v
+	}
+	return s
+}
+
+// Stack appends the column vector b to the column vector a, placing the result
+// in the receiver.
+func (m *Vec) Stack(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != 1 || bc != 1 || m == a || m == b {
+		panic(opError(ErrShape, "Vec.Stack", m, a, b))
+	}
+	if len(*m) == 0 {
+		*m = use(*m, ar+br)
+	} else if len(*m) != ar+br {
+		panic(opError(ErrShape, "Vec.Stack", m, a, b))
+	}
+
+	w := *m
+	if ar != 0 {
+		ad, ai := vectorOf(a)
+		blasEngine.Dcopy(ar, ad, ai, w, 1)
+	}
+	if br != 0 {
+		bd, bi := vectorOf(b)
+		blasEngine.Dcopy(br, bd, bi, w[ar:], 1)
+	}
+}
+
+// Equals returns whether the receiver and b have the same shape and elements.
+func (m Vec) Equals(b Matrix) bool {
+	if br, bc := b.Dims(); br != len(m) || bc != 1 {
+		return false
+	}
+	bd, bi := vectorOf(b)
+	for i, v := range m {
+		if v != bd[i*bi] {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualsApprox returns whether the receiver and b have the same shape and
+// elements that differ by no more than epsilon.
+func (m Vec) EqualsApprox(b Matrix, epsilon float64) bool {
+	if br, bc := b.Dims(); br != len(m) || bc != 1 {
+		return false
+	}
+	bd, bi := vectorOf(b)
+	for i, v := range m {
+		if math.Abs(v-bd[i*bi]) > epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadRawMatrix sets the receiver to the data of the single column RawMatrix
+// b. LoadRawMatrix panics with ErrIllegalStride if the column is not stored
+// contiguously.
+func (m *Vec) LoadRawMatrix(b RawMatrix) {
+	if b.Order != BlasOrder {
+		panic(ErrIllegalOrder)
+	}
+	if b.Cols != 1 {
+		panic(opError(ErrShape, "Vec.LoadRawMatrix", &Dense{mat: b}))
+	}
+	if b.Stride != 1 && b.Rows > 1 {
+		panic(ErrIllegalStride)
+	}
+	*m = b.Data[:b.Rows]
+}
+
+// RawMatrix returns the receiver as a single column RawMatrix sharing its data.
+func (m Vec) RawMatrix() RawMatrix {
+	return RawMatrix{
+		Order:  BlasOrder,
+		Rows:   len(m),
+		Cols:   1,
+		Stride: 1,
+		Data:   m,
+	}
+}
+
+// Mul takes the matrix product of a and the column vector b, placing the result
+// in the receiver.
 func (m *Vec) Mul(a, b Matrix) {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
 
-	if ac != br {
-		panic(ErrShape)
+	if ac != br || bc != 1 {
+		panic(opError(ErrShape, "Vec.Mul", m, a, b))
+	}
+
+	bv, bi := vectorOf(b)
+	au, trans := untranspose(a)
+	shared := m == a || m == b || overlaps(*m, 1, len(*m), bv, bi, br)
+	if au, ok := au.(RawMatrixer); ok {
+		shared = shared || rawOverlaps(m.RawMatrix(), au.RawMatrix())
 	}
 
 	var w Vec
-	if m != a && m != b {
+	if !shared {
 		w = *m
 	}
 	if len(w) == 0 {
 		w = use(w, ar)
-	} else if ar != len(w) || bc != 1 {
-		panic(ErrShape)
+	} else if ar != len(w) {
+		panic(opError(ErrShape, "Vec.Mul", m, a, b))
 	}
 
-	bv := *b.(*Vec) // This is a temporary restriction.
-
-	if a, ok := a.(RawMatrixer); ok {
-		amat := a.RawMatrix()
+	if au, ok := au.(RawMatrixer); ok {
+		amat := au.RawMatrix()
 		blasEngine.Dgemv(BlasOrder,
-			blas.NoTrans,
-			ar, ac,
+			trans,
+			amat.Rows, amat.Cols,
 			1.,
 			amat.Data, amat.Stride,
-			bv, 1,
+			bv, bi,
 			0.,
 			w, 1)
 		*m = w
@@ -95,7 +495,7 @@ func (m *Vec) Mul(a, b Matrix) {
 	if a, ok := a.(Vectorer); ok {
 		row := make([]float64, ac)
 		for r := 0; r < ar; r++ {
-			w[r] = blasEngine.Ddot(ac, a.Row(row, r), 1, bv, 1)
+			w[r] = blasEngine.Ddot(ac, a.Row(row, r), 1, bv, bi)
 		}
 		*m = w
 		return
@@ -108,7 +508,7 @@ func (m *Vec) Mul(a, b Matrix) {
 		}
 		var v float64
 		for i, e := range row {
-			v += e * bv[i]
+			v += e * bv[i*bi]
 		}
 		w[r] = v
 	}
@@ -0,0 +1,136 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	"math"
+
+	check "launchpad.net/gocheck"
+)
+
+func (s *S) TestVecElementwise(c *check.C) {
+	d := NewDense(flatten([][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 9},
+	}))
+	var col Dense
+	col.View(d, 0, 1, 3, 1)
+	var row Dense
+	row.View(d, 2, 0, 1, 3)
+
+	for i, test := range []struct {
+		a, b Matrix
+	}{
+		{a: Vec{1, -2, 3}, b: Vec{0.5, 4, -1}},
+		{a: Vec{1, -2, 3}, b: &col},
+		{a: row.T(), b: &col},
+		{a: NewDense(3, 1, []float64{1, 1, 1}), b: row.T()},
+	} {
+		var add, sub, mul, scale Vec
+		add.Add(test.a, test.b)
+		sub.Sub(test.a, test.b)
+		mul.MulElem(test.a, test.b)
+		scale.Scale(-2, test.a)
+		var dot, bb float64
+		for j := 0; j < 3; j++ {
+			a, b := test.a.At(j, 0), test.b.At(j, 0)
+			c.Check(add[j], check.Equals, a+b, check.Commentf("Test %d", i))
+			c.Check(sub[j], check.Equals, a-b, check.Commentf("Test %d", i))
+			c.Check(mul[j], check.Equals, a*b, check.Commentf("Test %d", i))
+			c.Check(scale[j], check.Equals, -2*a, check.Commentf("Test %d", i))
+			dot += a * b
+			bb += b * b
+		}
+		c.Check(add.Dot(test.b)-sub.Dot(test.b), check.Equals, 2*bb, check.Commentf("Test %d", i))
+		v := make(Vec, 3)
+		v.Clone(test.a)
+		c.Check(v.Dot(test.b), check.Equals, dot, check.Commentf("Test %d", i))
+	}
+
+	// Results written through a view update the parent.
+	v := Vec{10, 20, 30}
+	var t Vec
+	t.View(d.T(), 0, 1, 3, 1)
+	t.Add(t, v)
+	c.Check(d.At(1, 0), check.Equals, 14.)
+	c.Check(d.At(1, 2), check.Equals, 36.)
+
+	// The receiver may be either operand.
+	a, b := Vec{1, 2, 3}, Vec{4, 5, 6}
+	b.Sub(a, b)
+	c.Check(b, check.DeepEquals, Vec{-3, -3, -3})
+	a.Add(b, a)
+	c.Check(a, check.DeepEquals, Vec{-2, -1, 0})
+
+	c.Check(func() { var v Vec; v.View(d, 0, 0, 3, 1) }, check.PanicMatches, ".*illegal stride.*")
+	c.Check(func() { a.Add(a, Vec{1, 2}) }, check.PanicMatches, ".*dimension mismatch.*")
+}
+
+func (s *S) TestVecReductions(c *check.C) {
+	v := Vec{3, -4, 0, 1}
+	c.Check(v.Sum(), check.Equals, 0.)
+	c.Check(math.Abs(v.Norm(0)-math.Sqrt(26)) < 1e-14, check.Equals, true)
+	c.Check(v.Norm(1), check.Equals, 8.)
+	c.Check(v.Norm(math.Inf(1)), check.Equals, 4.)
+	c.Check(v.Norm(math.Inf(-1)), check.Equals, 0.)
+	c.Check(Vec{3, 4}.Norm(2), check.Equals, 5.)
+	c.Check(v.Norm(2), check.Equals, v.Norm(0))
+	c.Check(v.Norm(-2), check.Equals, v.Norm(0))
+	c.Check(Vec{}.Norm(math.Inf(-1)), check.Equals, 0.)
+	c.Check(func() { v.Norm(3) }, check.PanicMatches, `mat64: invalid norm order for matrix: Vec.Norm\(4x1\)`)
+
+	c.Check(v.Equals(NewDense(4, 1, []float64{3, -4, 0, 1})), check.Equals, true)
+	c.Check(v.Equals(NewDense(1, 4, []float64{3, -4, 0, 1})), check.Equals, false)
+	c.Check(v.EqualsApprox(Vec{3, -4, 1e-10, 1}, 1e-9), check.Equals, true)
+
+	var sq Vec
+	sq.Apply(func(r, c int, x float64) float64 { return x * x }, v)
+	c.Check(sq, check.DeepEquals, Vec{9, 16, 0, 1})
+
+	var st Vec
+	st.Stack(Vec{1, 2}, NewDense(2, 1, []float64{3, 4}))
+	c.Check(st, check.DeepEquals, Vec{1, 2, 3, 4})
+}
+
+func (s *S) TestVecMul(c *check.C) {
+	a := NewDense(flatten([][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+	}))
+	d := NewDense(flatten([][]float64{
+		{1, 0},
+		{-1, 2},
+		{2, 1},
+	}))
+	var col Dense
+	col.View(d, 0, 0, 3, 1)
+
+	var v Vec
+	v.Mul(a, &col)
+	c.Check(v, check.DeepEquals, Vec{5, 11})
+	v.Mul(a, Vec{1, -1, 2})
+	c.Check(v, check.DeepEquals, Vec{5, 11})
+
+	var w Vec
+	w.Mul(a.T(), v)
+	c.Check(w, check.DeepEquals, Vec{49, 65, 81})
+
+	// The receiver may share storage with a.
+	sq := NewDense(flatten([][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 9},
+	}))
+	x := Vec(sq.RowView(0))
+	x.Mul(sq.T(), Vec{1, 1, 1})
+	c.Check(x, check.DeepEquals, Vec{12, 15, 18})
+
+	var r RawMatrix = v.RawMatrix()
+	var u Vec
+	u.LoadRawMatrix(r)
+	u[0] = 0
+	c.Check(v[0], check.Equals, 0.)
+}
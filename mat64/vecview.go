@@ -0,0 +1,201 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+var (
+	vecView *VecView
+
+	_ Matrix  = vecView
+	_ Mutable = vecView
+
+	_ Viewer      = vecView
+	_ Subvectorer = vecView
+	_ Copier      = vecView
+
+	_ Adder  = vecView
+	_ Suber  = vecView
+	_ Dotter = vecView
+	_ Scaler = vecView
+
+	_ Normer = vecView
+	_ Sumer  = vecView
+
+	_ RawMatrixer = vecView
+)
+
+// VecView is a strided view of a column vector of length N whose element i is
+// Data[i*Inc]. The fields may be passed directly to level-1 BLAS routines.
+// A VecView returned by Dense.ColView or Dense.DiagView shares the matrix data,
+// so changes made through the view are reflected in the matrix and vice versa.
+type VecView struct {
+	Data []float64
+	Inc  int
+	N    int
+}
+
+func (v VecView) At(r, c int) float64 {
+	if checkBounds && (c != 0 || uint(r) >= uint(v.N)) {
+		panic(indexError("VecView.At", v, r, c))
+	}
+	return v.Data[r*v.Inc]
+}
+
+func (v VecView) Set(r, c int, f float64) {
+	if checkBounds && (c != 0 || uint(r) >= uint(v.N)) {
+		panic(indexError("VecView.Set", v, r, c))
+	}
+	v.Data[r*v.Inc] = f
+}
+
+func (v VecView) Dims() (r, c int) { return v.N, 1 }
+
+// Len returns the number of elements in the view.
+func (v VecView) Len() int { return v.N }
+
+// RawMatrix returns the view as a single column RawMatrix with stride Inc, so
+// that it may take part in Dense operations without being copied.
+func (v VecView) RawMatrix() RawMatrix {
+	return RawMatrix{
+		Order:  BlasOrder,
+		Rows:   v.N,
+		Cols:   1,
+		Stride: v.Inc,
+		Data:   v.Data,
+	}
+}
+
+// newVecView returns the view of n elements of data with increment inc.
+func newVecView(data []float64, inc, n int) VecView {
+	if n == 0 {
+		return VecView{Inc: inc}
+	}
+	return VecView{Data: data[:(n-1)*inc+1], Inc: inc, N: n}
+}
+
+// ColView returns a strided view of column c of the receiver.
+func (m *Dense) ColView(c int) VecView {
+	if uint(c) >= uint(m.mat.Cols) {
+		panic(indexError("Dense.ColView", m, c))
+	}
+	return newVecView(m.mat.Data[c:], m.mat.Stride, m.mat.Rows)
+}
+
+// DiagView returns a strided view of the leading diagonal of the receiver.
+func (m *Dense) DiagView() VecView {
+	return newVecView(m.mat.Data, m.mat.Stride+1, min(m.mat.Rows, m.mat.Cols))
+}
+
+// View sets the receiver to a view of the r elements of column j of a starting
+// at row i. The column may be strided, so a may be any RawMatrixer or a
+// Transpose of one. View panics with ErrIllegalStride if a is not backed by
+// such storage.
+func (v *VecView) View(a Matrix, i, j, r, c int) {
+	ar, ac := a.Dims()
+	if c != 1 {
+		panic(opError(ErrShape, "VecView.View", v, a))
+	}
+	if i < 0 || j < 0 || r < 0 || i+r > ar || j >= ac {
+		panic(indexError("VecView.View", a, i, j))
+	}
+	switch a := a.(type) {
+	case Transpose:
+		if raw, ok := a.Matrix.(RawMatrixer); ok {
+			amat := raw.RawMatrix()
+			*v = newVecView(amat.Data[j*amat.Stride+i:], 1, r)
+			return
+		}
+	case RawMatrixer:
+		amat := a.RawMatrix()
+		*v = newVecView(amat.Data[i*amat.Stride+j:], amat.Stride, r)
+		return
+	}
+	panic(opError(ErrIllegalStride, "VecView.View", v, a))
+}
+
+// Subvector sets the receiver to a view of the n elements of the column vector a
+// starting at element i.
+func (v *VecView) Subvector(a Matrix, i, n int) {
+	v.View(a, i, 0, n, 1)
+}
+
+// Copy copies the elements of the column vector a into the receiver, returning
+// the number of elements copied.
+func (v *VecView) Copy(a Matrix) (r, c int) {
+	ar, ac := a.Dims()
+	if ac != 1 {
+		panic(opError(ErrShape, "VecView.Copy", v, a))
+	}
+	n := min(ar, v.N)
+	if n != 0 {
+		ad, ai := vectorOf(a)
+		blasEngine.Dcopy(n, ad, ai, v.Data, v.Inc)
+	}
+	return n, 1
+}
+
+// reuseAs checks that the operands are column vectors with the receiver's
+// length, allocating a contiguous vector if the receiver is empty.
+func (v *VecView) reuseAs(op string, a Matrix, others ...Matrix) {
+	r, c := a.Dims()
+	if c != 1 {
+		panic(opError(ErrShape, op, append([]Matrix{v, a}, others...)...))
+	}
+	for _, b := range others {
+		if br, bc := b.Dims(); br != r || bc != 1 {
+			panic(opError(ErrShape, op, append([]Matrix{v, a}, others...)...))
+		}
+	}
+	if v.N == 0 {
+		*v = newVecView(make([]float64, r), 1, r)
+	} else if v.N != r {
+		panic(opError(ErrShape, op, append([]Matrix{v, a}, others...)...))
+	}
+}
+
+// Add performs element-wise addition of a and b, placing the result in the
+// receiver.
+func (v *VecView) Add(a, b Matrix) {
+	v.reuseAs("VecView.Add", a, b)
+	addTo(v.Data, v.Inc, v.N, a, b)
+}
+
+// Sub performs element-wise subtraction of b from a, placing the result in the
+// receiver.
+func (v *VecView) Sub(a, b Matrix) {
+	v.reuseAs("VecView.Sub", a, b)
+	subTo(v.Data, v.Inc, v.N, a, b)
+}
+
+// Scale multiplies the elements of a by f, placing the result in the receiver.
+func (v *VecView) Scale(f float64, a Matrix) {
+	v.reuseAs("VecView.Scale", a)
+	scaleTo(v.Data, v.Inc, v.N, f, a)
+}
+
+// Dot returns the dot product of the receiver and the column vector b.
+func (v VecView) Dot(b Matrix) float64 {
+	if br, bc := b.Dims(); br != v.N || bc != 1 {
+		panic(opError(ErrShape, "VecView.Dot", v, b))
+	}
+	if v.N == 0 {
+		return 0
+	}
+	bd, bi := vectorOf(b)
+	return blasEngine.Ddot(v.N, v.Data, v.Inc, bd, bi)
+}
+
+// Norm returns the specified norm of the receiver as for Vec.Norm.
+func (v VecView) Norm(o float64) float64 {
+	return vectorNorm("VecView.Norm", v.Data, v.Inc, v.N, o)
+}
+
+// Sum returns the sum of the elements of the receiver.
+func (v VecView) Sum() float64 {
+	var s float64
+	for i := 0; i < v.N; i++ {
+		s += v.Data[i*v.Inc]
+	}
+	return s
+}
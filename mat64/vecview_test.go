@@ -0,0 +1,109 @@
+// Copyright ©2013 The gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat64
+
+import (
+	check "launchpad.net/gocheck"
+)
+
+func (s *S) TestVecView(c *check.C) {
+	d := NewDense(flatten([][]float64{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	}))
+
+	col := d.ColView(1)
+	c.Check(col.Len(), check.Equals, 3)
+	c.Check(Vec{2, 6, 10}.Equals(col), check.Equals, true)
+	diag := d.DiagView()
+	c.Check(diag.Len(), check.Equals, 3)
+	c.Check(diag.Sum(), check.Equals, 18.)
+
+	// Writes through the view reach the matrix and vice versa.
+	col.Set(2, 0, -10)
+	c.Check(d.At(2, 1), check.Equals, -10.)
+	d.Set(0, 1, 20)
+	c.Check(col.At(0, 0), check.Equals, 20.)
+
+	// Views work with level-1 BLAS and the vector operations.
+	blasEngine.Daxpy(diag.N, 1, col.Data, col.Inc, diag.Data, diag.Inc)
+	c.Check(d.At(0, 0), check.Equals, 21.)
+	c.Check(d.At(1, 1), check.Equals, 12.)
+	c.Check(d.At(2, 2), check.Equals, 1.)
+
+	col.Scale(2, col)
+	c.Check(Vec(d.Col(nil, 1)), check.DeepEquals, Vec{40, 24, -20})
+	col.Sub(col, d.ColView(3))
+	c.Check(Vec(d.Col(nil, 1)), check.DeepEquals, Vec{36, 16, -32})
+	c.Check(col.Dot(Vec{1, 1, 1}), check.Equals, 20.)
+	c.Check(col.Norm(1), check.Equals, 84.)
+	c.Check(col.Norm(2), check.Equals, col.Norm(0))
+	c.Check(func() { col.Norm(3) }, check.PanicMatches, `mat64: invalid norm order for matrix: VecView.Norm\(3x1\)`)
+
+	var v Vec
+	v.Add(d.ColView(0), diag)
+	c.Check(v, check.DeepEquals, Vec{42, 21, 10})
+
+	var sub VecView
+	sub.Subvector(d.ColView(2), 1, 2)
+	c.Check(Vec{7, 1}.Equals(sub), check.Equals, true)
+	sub.Copy(Vec{0, 0})
+	c.Check(d.At(2, 2), check.Equals, 0.)
+
+	var row VecView
+	row.View(d.T(), 1, 1, 3, 1)
+	c.Check(Vec{16, 0, 8}.Equals(row), check.Equals, true)
+
+	// A view can be an operand of Dense operations.
+	var p Dense
+	p.Mul(d, NewDense(4, 1, []float64{1, 0, 0, 0}))
+	var q Dense
+	q.Add(&p, d.ColView(0))
+	c.Check(q.Equals(NewDense(3, 1, []float64{42, 10, 18})), check.Equals, true)
+
+	// Views of the same matrix may overlap without starting at the same
+	// element or sharing an increment.
+	for i, test := range []struct {
+		op   func(m *Dense)
+		want []float64
+	}{
+		{
+			op:   func(m *Dense) { v := m.DiagView(); v.Add(m.ColView(0), Vec{10, 20}) },
+			want: []float64{11, 2, 3, 23},
+		},
+		{
+			op:   func(m *Dense) { v := m.ColView(1); v.Add(m.ColView(0), m.DiagView()) },
+			want: []float64{1, 2, 3, 7},
+		},
+		{
+			op:   func(m *Dense) { v := m.ColView(0); v.Sub(m.DiagView(), m.ColView(1)) },
+			want: []float64{-1, 2, 0, 4},
+		},
+		{
+			op:   func(m *Dense) { v := m.DiagView(); v.Sub(Vec{1, 1}, m.ColView(0)) },
+			want: []float64{0, 2, 3, -2},
+		},
+		{
+			op:   func(m *Dense) { v := m.DiagView(); v.Scale(2, m.ColView(0)) },
+			want: []float64{2, 2, 3, 6},
+		},
+		{
+			op: func(m *Dense) {
+				var v VecView
+				v.View(m.T(), 0, 0, 2, 1)
+				v.Add(v, m.DiagView())
+			},
+			want: []float64{2, 6, 3, 4},
+		},
+	} {
+		m := NewDense(2, 2, []float64{1, 2, 3, 4})
+		test.op(m)
+		c.Check(m.Equals(NewDense(2, 2, test.want)), check.Equals, true, check.Commentf("Test %d: %v", i, m))
+	}
+
+	c.Check(func() { d.ColView(4) }, check.PanicMatches, ".*index out of range.*")
+	c.Check(func() { col.Add(col, Vec{1, 2}) }, check.PanicMatches, ".*dimension mismatch.*")
+}